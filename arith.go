@@ -0,0 +1,128 @@
+package num
+
+// ARITHMETIC FUNCTIONS
+// Each of these is multiplicative (or additive) so is computed directly from the prime
+// power factorization of n rather than by enumerating its divisors. All return 0 for n < 1.
+
+// Multiplicative evaluates the multiplicative function whose value on each prime power p^e
+// is given by fn, i.e the product of fn(p, e) over every prime power in f
+func (f Factorization) Multiplicative(fn func(p, e Int) Int) Int {
+	res := Int(1)
+
+	for _, pp := range f {
+		res *= fn(pp.Prime, pp.Exp)
+	}
+
+	return res
+}
+
+// Multiplicative evaluates the multiplicative function defined on prime powers by fn at n
+func (n Int) Multiplicative(fn func(p, e Int) Int) Int {
+	if n < 1 {
+		return 0
+	}
+
+	return n.Factorize().Multiplicative(fn)
+}
+
+// Sigma returns the sum of the kth powers of the divisors of n. Sigma(0) is the number
+// of divisors and Sigma(1) is their sum.
+func (n Int) Sigma(k Int) Int {
+	return n.Multiplicative(func(p, e Int) Int {
+		pk := Int(1)
+		for i := Int(0); i < k; i++ {
+			pk *= p
+		}
+
+		term, t := Int(1), Int(1)
+		for i := Int(0); i < e; i++ {
+			t *= pk
+			term += t
+		}
+
+		return term
+	})
+}
+
+// Tau returns the number of divisors of n
+func (n Int) Tau() Int {
+	return n.Multiplicative(func(p, e Int) Int {
+		return e + 1
+	})
+}
+
+// Mobius returns the Möbius function mu(n): 0 if n has a squared prime factor, otherwise
+// 1 or -1 for an even or odd number of prime factors respectively
+func (n Int) Mobius() Int {
+	return n.Multiplicative(func(p, e Int) Int {
+		if e > 1 {
+			return 0
+		}
+		return -1
+	})
+}
+
+// Liouville returns the Liouville function lambda(n), which is -1 raised to the power of Omega(n)
+func (n Int) Liouville() Int {
+	return n.Multiplicative(func(p, e Int) Int {
+		if e%2 == 0 {
+			return 1
+		}
+		return -1
+	})
+}
+
+// Carmichael returns the Carmichael function lambda(n), the smallest m such that a^m = 1 mod n
+// for every a coprime to n
+func (n Int) Carmichael() Int {
+	if n < 1 {
+		return 0
+	}
+
+	res := Int(1)
+	for _, pp := range n.Factorize() {
+		l := pp.Prime - 1
+		for i := Int(1); i < pp.Exp; i++ {
+			l *= pp.Prime
+		}
+
+		// The group of units modulo 2^e for e >= 3 is not cyclic
+		if pp.Prime == 2 && pp.Exp >= 3 {
+			l /= 2
+		}
+
+		res = res.LCM(l)
+	}
+
+	return res
+}
+
+// Omega returns omega(n), the number of distinct prime factors of n
+func (n Int) Omega() Int {
+	if n < 1 {
+		return 0
+	}
+
+	return Int(len(n.Factorize()))
+}
+
+// BigOmega returns Omega(n), the number of prime factors of n counted with multiplicity
+func (n Int) BigOmega() Int {
+	if n < 1 {
+		return 0
+	}
+
+	res := Int(0)
+	for _, pp := range n.Factorize() {
+		res += pp.Exp
+	}
+
+	return res
+}
+
+// Rad returns the radical of n, the product of its distinct prime factors
+func (n Int) Rad() Int {
+	return n.Multiplicative(func(p, e Int) Int {
+		return p
+	})
+}
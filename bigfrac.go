@@ -0,0 +1,166 @@
+package num
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// BigFrac represents a (possibly mixed) fraction of arbitrary size by its Int(eger), Num(erator)
+// and Den(ominator) values. It mirrors Frac for values that do not fit in an Int.
+type BigFrac struct {
+	Num, Den, Int *big.Int
+}
+
+// NewBigFrac returns a new BigFrac. n and d are copied.
+func NewBigFrac(n, d *big.Int) *BigFrac {
+	f := &BigFrac{
+		Num: new(big.Int).Set(n),
+		Den: new(big.Int).Set(d),
+		Int: new(big.Int),
+	}
+
+	if d.Sign() != 0 {
+		f.Int.Quo(n, d)
+	}
+
+	return f
+}
+
+// RatToBigFrac returns r as a reduced BigFrac
+func RatToBigFrac(r *big.Rat) *BigFrac {
+	return NewBigFrac(r.Num(), r.Denom())
+}
+
+// Big returns f as a BigFrac
+func (f *Frac) Big() *BigFrac {
+	return NewBigFrac(big.NewInt(int64(f.Num)), big.NewInt(int64(f.Den)))
+}
+
+// Rat returns f as a big.Rat. It panics if the denominator of f is 0.
+func (f *Frac) Rat() *big.Rat {
+	return big.NewRat(int64(f.Num), int64(f.Den))
+}
+
+// Frac returns f as a Frac, or ErrOverflow if its numerator or denominator does not fit in an Int
+func (f *BigFrac) Frac() (*Frac, error) {
+	if !f.Num.IsInt64() || !f.Den.IsInt64() {
+		return nil, ErrOverflow
+	}
+
+	return NewFrac(Int(f.Num.Int64()), Int(f.Den.Int64())), nil
+}
+
+// Rat returns f as a big.Rat. It panics if the denominator of f is 0.
+func (f *BigFrac) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(f.Num, f.Den)
+}
+
+// GCD returns the Greatest Common Divisor of the numerator and denominator of f
+func (f *BigFrac) GCD() *big.Int {
+	return new(big.Int).GCD(nil, nil, new(big.Int).Abs(f.Num), new(big.Int).Abs(f.Den))
+}
+
+func (f *BigFrac) String() string {
+	return fmt.Sprintf("%v/%v", f.Num, f.Den)
+}
+
+// Reduce simplifies f, moving any sign into the numerator, and returns it
+func (f *BigFrac) Reduce() *BigFrac {
+	if f.Den.Sign() < 0 {
+		f.Num.Neg(f.Num)
+		f.Den.Neg(f.Den)
+	}
+
+	if gcd := f.GCD(); gcd.Sign() != 0 {
+		f.Num.Quo(f.Num, gcd)
+		f.Den.Quo(f.Den, gcd)
+	}
+
+	return f
+}
+
+// Inverse returns the inverse of f as a new BigFrac
+func (f *BigFrac) Inverse() *BigFrac {
+	return NewBigFrac(f.Den, f.Num)
+}
+
+// CF emits the Continued Fraction integer terms of f using Euclid's algorithm. Like Frac.CF
+// the leading integer term is omitted.
+func (f *BigFrac) CF() []*big.Int {
+	var (
+		res  []*big.Int
+		n, d = new(big.Int).Set(f.Num), new(big.Int).Set(f.Den)
+		m    = new(big.Int)
+	)
+
+	for d.Sign() != 0 {
+		q := new(big.Int)
+		q.QuoRem(n, d, m)
+		res = append(res, q)
+		n, d, m = d, m, n
+	}
+
+	if len(res) == 0 {
+		return nil
+	}
+
+	return res[1:]
+}
+
+// Sign returns -1, 0 or 1 according to the sign of f
+func (f *BigFrac) Sign() Int {
+	return Int(f.Num.Sign() * f.Den.Sign())
+}
+
+// Cmp returns -1, 0 or 1 as f is less than, equal to or greater than g
+func (f *BigFrac) Cmp(g *BigFrac) int {
+	return f.Rat().Cmp(g.Rat())
+}
+
+// Equal returns true if f and g represent the same value
+func (f *BigFrac) Equal(g *BigFrac) bool {
+	return f.Cmp(g) == 0
+}
+
+// Add returns f+g as a new reduced BigFrac
+func (f *BigFrac) Add(g *BigFrac) *BigFrac {
+	return RatToBigFrac(new(big.Rat).Add(f.Rat(), g.Rat()))
+}
+
+// Sub returns f-g as a new reduced BigFrac
+func (f *BigFrac) Sub(g *BigFrac) *BigFrac {
+	return RatToBigFrac(new(big.Rat).Sub(f.Rat(), g.Rat()))
+}
+
+// Mul returns f*g as a new reduced BigFrac
+func (f *BigFrac) Mul(g *BigFrac) *BigFrac {
+	return RatToBigFrac(new(big.Rat).Mul(f.Rat(), g.Rat()))
+}
+
+// Div returns f/g as a new reduced BigFrac, or ErrZeroDenominator if g is 0
+func (f *BigFrac) Div(g *BigFrac) (*BigFrac, error) {
+	if g.Sign() == 0 {
+		return nil, ErrZeroDenominator
+	}
+
+	return RatToBigFrac(new(big.Rat).Quo(f.Rat(), g.Rat())), nil
+}
+
+// Pow returns f^k as a new reduced BigFrac. A negative k raises the inverse of f, which
+// returns ErrZeroDenominator if f is 0.
+func (f *BigFrac) Pow(k Int) (*BigFrac, error) {
+	r := f.Rat()
+	if k < 0 {
+		if r.Sign() == 0 {
+			return nil, ErrZeroDenominator
+		}
+		r.Inv(r)
+		k = -k
+	}
+
+	e := big.NewInt(int64(k))
+	n := new(big.Int).Exp(r.Num(), e, nil)
+	d := new(big.Int).Exp(r.Denom(), e, nil)
+
+	return NewBigFrac(n, d), nil
+}
@@ -0,0 +1,279 @@
+package num
+
+import (
+	"math/big"
+)
+
+// LAZY CONTINUED FRACTIONS
+// A CFStream produces the terms [a0; a1, a2, ...] of a continued fraction on demand, so infinite
+// expansions such as e or pi can be consumed to any depth.
+
+// CFStream is a continued fraction whose terms are produced lazily by a generator. The generator
+// returns false once a finite continued fraction has no more terms.
+type CFStream struct {
+	next func() (Int, bool)
+}
+
+// NewCFStream returns a CFStream whose terms are produced by successive calls to next
+func NewCFStream(next func() (Int, bool)) *CFStream {
+	return &CFStream{next: next}
+}
+
+// Next returns the next term of c, or false if c is finite and exhausted
+func (c *CFStream) Next() (Int, bool) {
+	return c.next()
+}
+
+// Terms returns a channel of the remaining terms of c
+func (c *CFStream) Terms() chan Int {
+	ch := make(chan Int, 1)
+
+	go func() {
+		defer close(ch)
+
+		for a, ok := c.next(); ok; a, ok = c.next() {
+			ch <- a
+		}
+	}()
+
+	return ch
+}
+
+// Convergents returns a stream of the convergents of the remaining terms of c
+func (c *CFStream) Convergents() chan *big.Rat {
+	ch := make(chan *big.Rat, 1)
+
+	go func() {
+		defer close(ch)
+
+		var (
+			h0, h1 = big.NewInt(0), big.NewInt(1)
+			k0, k1 = big.NewInt(1), big.NewInt(0)
+		)
+
+		for a, ok := c.next(); ok; a, ok = c.next() {
+			b := big.NewInt(int64(a))
+
+			h0, h1 = h1, new(big.Int).Add(new(big.Int).Mul(b, h1), h0)
+			k0, k1 = k1, new(big.Int).Add(new(big.Int).Mul(b, k1), k0)
+
+			ch <- new(big.Rat).SetFrac(h1, k1)
+		}
+	}()
+
+	return ch
+}
+
+// SetCF returns a CFStream of the terms in s. If recurring is true the terms after the first
+// repeat forever, matching the convention of Set.Convergents.
+func SetCF(s Set, recurring bool) *CFStream {
+	i := 0
+
+	return NewCFStream(func() (Int, bool) {
+		if i >= len(s) {
+			if !recurring || len(s) < 2 {
+				return 0, false
+			}
+			i = 1
+		}
+
+		i++
+		return s[i-1], true
+	})
+}
+
+// ECF returns the continued fraction of e = [2; 1, 2, 1, 1, 4, 1, 1, 6, ...]
+func ECF() *CFStream {
+	i := Int(0)
+
+	return NewCFStream(func() (Int, bool) {
+		i++
+		switch {
+		case i == 1:
+			return 2, true
+		case i%3 == 0:
+			return 2 * i / 3, true
+		}
+		return 1, true
+	})
+}
+
+// PhiCF returns the continued fraction of the golden ratio = [1; 1, 1, ...]
+func PhiCF() *CFStream {
+	return NewCFStream(func() (Int, bool) {
+		return 1, true
+	})
+}
+
+// Tan1CF returns the continued fraction of tan(1) = [1; 1, 1, 3, 1, 5, 1, 7, ...]
+func Tan1CF() *CFStream {
+	i := Int(0)
+
+	return NewCFStream(func() (Int, bool) {
+		i++
+		if i%2 == 0 {
+			return i - 1, true
+		}
+		return 1, true
+	})
+}
+
+// SqrtCF returns the continued fraction of sqrt(n), which is finite if n is square
+func SqrtCF(n Int) *CFStream {
+	q, err := NewQuadraticIrrational(0, 1, n, 1)
+	if err != nil {
+		return SetCF(nil, false)
+	}
+
+	pre, period := q.CF()
+	i := 0
+
+	return NewCFStream(func() (Int, bool) {
+		i++
+		switch {
+		case i <= len(pre):
+			return pre[i-1], true
+		case len(period) == 0:
+			return 0, false
+		}
+		return period[(i-len(pre)-1)%len(period)], true
+	})
+}
+
+// PiDigits returns an unbounded stream of the decimal digits of pi, 3, 1, 4, 1, 5, ...
+// using Gibbons' streaming spigot algorithm
+func PiDigits() chan Int {
+	c := make(chan Int, 1)
+
+	go func() {
+		defer close(c)
+
+		next := piSpigot()
+		for {
+			c <- next()
+		}
+	}()
+
+	return c
+}
+
+// piSpigot returns a function that yields successive decimal digits of pi
+func piSpigot() func() Int {
+	var (
+		q, r, t = big.NewInt(1), big.NewInt(0), big.NewInt(1)
+		k, n, l = big.NewInt(1), big.NewInt(3), big.NewInt(3)
+
+		a, b = new(big.Int), new(big.Int)
+		two  = big.NewInt(2)
+		ten  = big.NewInt(10)
+	)
+
+	return func() Int {
+		for {
+			// Emit n once 4q + r - t < n*t, i.e the digit can no longer change
+			a.Add(a.Lsh(q, 2), r)
+			a.Sub(a, t)
+			if a.Cmp(b.Mul(n, t)) < 0 {
+				d := Int(n.Int64())
+
+				// n = 10*(3q + r)/t - 10n, r = 10*(r - n*t), q = 10q
+				a.Mul(q, big.NewInt(3))
+				a.Add(a, r)
+				a.Mul(a, ten)
+				a.Quo(a, t)
+				b.Mul(n, ten)
+
+				r.Sub(r, new(big.Int).Mul(n, t))
+				r.Mul(r, ten)
+				q.Mul(q, ten)
+				n.Sub(a, b)
+
+				return d
+			}
+
+			// n = (q*(7k + 2) + r*l) / (t*l), r = (2q + r)*l, q = q*k, t = t*l
+			a.Mul(k, big.NewInt(7))
+			a.Add(a, two)
+			a.Mul(a, q)
+			a.Add(a, new(big.Int).Mul(r, l))
+			b.Mul(t, l)
+			a.Quo(a, b)
+
+			r.Add(r, new(big.Int).Mul(q, two))
+			r.Mul(r, l)
+			q.Mul(q, k)
+			t.Set(b)
+			n.Set(a)
+			k.Add(k, big.NewInt(1))
+			l.Add(l, two)
+		}
+	}
+}
+
+// PiCF returns the exact continued fraction of pi = [3; 7, 15, 1, 292, ...]. Terms are emitted
+// once they are common to both ends of the interval given by the spigot digits seen so far.
+func PiCF() *CFStream {
+	var (
+		digit = piSpigot()
+		lo    = new(big.Rat)      // pi lies in (lo, lo + width)
+		width = big.NewRat(10, 1) // 10^-k after k digits
+		tenth = big.NewRat(1, 10) // scale of each new digit
+		m     = [4]*big.Int{big.NewInt(1), big.NewInt(0), big.NewInt(0), big.NewInt(1)}
+	)
+
+	// apply returns (m0*x + m1) / (m2*x + m3), or nil if the denominator vanishes
+	apply := func(x *big.Rat) *big.Rat {
+		n := new(big.Rat).Mul(x, new(big.Rat).SetInt(m[0]))
+		n.Add(n, new(big.Rat).SetInt(m[1]))
+
+		d := new(big.Rat).Mul(x, new(big.Rat).SetInt(m[2]))
+		d.Add(d, new(big.Rat).SetInt(m[3]))
+		if d.Sign() == 0 {
+			return nil
+		}
+
+		return n.Quo(n, d)
+	}
+
+	return NewCFStream(func() (Int, bool) {
+		for {
+			hi := new(big.Rat).Add(lo, width)
+			if xl, xh := apply(lo), apply(hi); xl != nil && xh != nil && sameSide(m, lo, hi) {
+				if a, b := floorRat(xl), floorRat(xh); a.Cmp(b) == 0 {
+					// x -> 1/(x - a) as a transform of pi
+					m = [4]*big.Int{
+						m[2], m[3],
+						new(big.Int).Sub(m[0], new(big.Int).Mul(a, m[2])),
+						new(big.Int).Sub(m[1], new(big.Int).Mul(a, m[3])),
+					}
+					return Int(a.Int64()), true
+				}
+			}
+
+			// Narrow the interval with another digit
+			width.Mul(width, tenth)
+			lo.Add(lo, new(big.Rat).Mul(width, big.NewRat(int64(digit()), 1)))
+		}
+	})
+}
+
+// sameSide returns true if the denominator of the transform m has the same non-zero sign at
+// both lo and hi, so that m is monotonic across the interval between them
+func sameSide(m [4]*big.Int, lo, hi *big.Rat) bool {
+	s := func(x *big.Rat) int {
+		d := new(big.Rat).Mul(x, new(big.Rat).SetInt(m[2]))
+		return d.Add(d, new(big.Rat).SetInt(m[3])).Sign()
+	}
+
+	sl := s(lo)
+	return sl != 0 && sl == s(hi)
+}
+
+// floorRat returns floor(x)
+func floorRat(x *big.Rat) *big.Int {
+	q, r := new(big.Int).QuoRem(x.Num(), x.Denom(), new(big.Int))
+	if r.Sign() < 0 {
+		q.Sub(q, big.NewInt(1))
+	}
+	return q
+}
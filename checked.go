@@ -0,0 +1,123 @@
+package num
+
+import (
+	"errors"
+	"math"
+	"strconv"
+)
+
+// CHECKED ARITHMETIC
+// Int arithmetic wraps silently on overflow. The *Checked variants below return ErrOverflow
+// instead of a wrapped result.
+
+// ErrOverflow is returned when the result of an operation does not fit in an Int
+var ErrOverflow = errors.New("num: Int overflow")
+
+// AddChecked returns n+m or ErrOverflow
+func (n Int) AddChecked(m Int) (Int, error) {
+	r := n + m
+	if (m > 0 && r < n) || (m < 0 && r > n) {
+		return 0, ErrOverflow
+	}
+
+	return r, nil
+}
+
+// SubChecked returns n-m or ErrOverflow
+func (n Int) SubChecked(m Int) (Int, error) {
+	r := n - m
+	if (m > 0 && r > n) || (m < 0 && r < n) {
+		return 0, ErrOverflow
+	}
+
+	return r, nil
+}
+
+// MulChecked returns n*m or ErrOverflow
+func (n Int) MulChecked(m Int) (Int, error) {
+	if n == 0 || m == 0 {
+		return 0, nil
+	}
+
+	r := n * m
+	if r/m != n || (n == -1 && m == math.MinInt64) || (m == -1 && n == math.MinInt64) {
+		return 0, ErrOverflow
+	}
+
+	return r, nil
+}
+
+// FactorialChecked returns n! or ErrOverflow
+func (n Int) FactorialChecked() (Int, error) {
+	var (
+		res = Int(1)
+		err error
+	)
+
+	for i := Int(2); i <= n; i++ {
+		if res, err = res.MulChecked(i); err != nil {
+			return 0, err
+		}
+	}
+
+	return res, nil
+}
+
+// ProductChecked returns the product of the set or ErrOverflow
+func (s Set) ProductChecked() (Int, error) {
+	var (
+		t   = Int(1)
+		err error
+	)
+
+	for _, n := range s {
+		if t, err = t.MulChecked(n); err != nil {
+			return 0, err
+		}
+	}
+
+	return t, nil
+}
+
+// ToIntChecked returns an Int by concatenating the elements of s or ErrOverflow
+func (s Set) ToIntChecked() (Int, error) {
+	var b []byte
+
+	for _, v := range s {
+		b = strconv.AppendInt(b, int64(v), 10)
+	}
+
+	n, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, ErrOverflow
+		}
+		return 0, err
+	}
+
+	return Int(n), nil
+}
+
+// polygonal returns i*(a*i - b)/d for d = 1 or 2, the ith term of a figurate sequence,
+// or ErrOverflow. When d is 2 the even factor is halved first so that no intermediate
+// value overflows unless the result does.
+func polygonal(i, a, b, d Int) (Int, error) {
+	t, err := a.MulChecked(i)
+	if err != nil {
+		return 0, err
+	}
+
+	if t, err = t.SubChecked(b); err != nil {
+		return 0, err
+	}
+
+	if d == 2 {
+		if i%2 == 0 {
+			i /= 2
+		} else {
+			t /= 2
+		}
+	}
+
+	return i.MulChecked(t)
+}
@@ -0,0 +1,169 @@
+package num
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// RECURRING EXPANSIONS
+// Every rational has a positional expansion that ends in a repeating cycle. The length of the
+// non-repeating prefix depends only on the factors the denominator shares with the base, and
+// the cycle length is the multiplicative order of the base modulo what is left.
+
+// Notation identifies how the repetend of an Expansion is marked when formatted
+type Notation int
+
+// Repetend notations
+const (
+	PARENTHESES Notation = iota // 0.1(6)
+	VINCULUM                    // 0.16̅ using a combining overline on each repeating digit
+)
+
+// digitChars are the characters used for digits in bases up to 36
+const digitChars = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// Expansion is the positional expansion of a fraction in Base, split into the integer part,
+// the non-repeating Prefix digits after the radix point and the Repetend digits that recur
+type Expansion struct {
+	Base     Int
+	Negative bool
+	Int      *big.Int
+	Prefix   Set
+	Repetend Set
+}
+
+// Expand returns the expansion of f in the given base, which must be between 2 and 36.
+// Every digit of the repetend is produced, of which there may be up to f.Den - 1.
+func (f *Frac) Expand(base Int) (*Expansion, error) {
+	return f.Big().Expand(base)
+}
+
+// Expand returns the expansion of f in the given base, which must be between 2 and 36.
+func (f *BigFrac) Expand(base Int) (*Expansion, error) {
+	if base < 2 || base > Int(len(digitChars)) {
+		return nil, fmt.Errorf("Expansion base out of range [%d]", base)
+	}
+
+	if f.Den.Sign() == 0 {
+		return nil, ErrZeroDenominator
+	}
+
+	var (
+		g = NewBigFrac(f.Num, f.Den).Reduce()
+		b = big.NewInt(int64(base))
+		n = new(big.Int).Abs(g.Num)
+		d = g.Den
+		r = new(big.Int)
+		q = new(big.Int)
+		e = &Expansion{Base: base, Negative: g.Num.Sign() < 0, Int: new(big.Int), Prefix: Set{}, Repetend: Set{}}
+	)
+
+	e.Int.QuoRem(n, d, r)
+
+	// digit appends the next digit of r/d to s
+	digit := func(s Set) Set {
+		r.Mul(r, b)
+		q.QuoRem(r, d, r)
+		return append(s, Int(q.Int64()))
+	}
+
+	for k := prefixLength(d, b); k > 0; k-- {
+		e.Prefix = digit(e.Prefix)
+	}
+
+	if r.Sign() != 0 {
+		r0 := new(big.Int).Set(r)
+		for e.Repetend = digit(e.Repetend); r.Cmp(r0) != 0; {
+			e.Repetend = digit(e.Repetend)
+		}
+	}
+
+	return e, nil
+}
+
+// CycleLength returns the length of the repetend of f in the given base without expanding it
+func (f *Frac) CycleLength(base Int) Int {
+	g, err := newReduced(f.Num, f.Den)
+	if err != nil {
+		return 0
+	}
+
+	// Remove every factor the denominator shares with the base
+	d := g.Den
+	for k := d.GCD(base); k != 1; k = d.GCD(base) {
+		d /= k
+	}
+
+	if d == 1 {
+		return 0
+	}
+
+	o, _ := base.MultiplicativeOrder(d)
+	return o
+}
+
+// prefixLength returns the number of non-repeating digits in the expansion of any reduced
+// fraction with denominator d in base b
+func prefixLength(d, b *big.Int) int {
+	var (
+		t = new(big.Int).Set(d)
+		g = new(big.Int)
+		k = 0
+	)
+
+	for g.GCD(nil, nil, t, b); g.Cmp(big.NewInt(1)) != 0; g.GCD(nil, nil, t, b) {
+		t.Quo(t, g)
+		k++
+	}
+
+	return k
+}
+
+// CycleLength returns the number of digits in the repetend of e
+func (e *Expansion) CycleLength() Int {
+	return Int(len(e.Repetend))
+}
+
+// String returns e with the repetend in parentheses, i.e 1/6 = 0.1(6), and satisfies the stringer interface
+func (e *Expansion) String() string {
+	return e.Format(PARENTHESES)
+}
+
+// Format returns e as a string with the repetend marked in notation n
+func (e *Expansion) Format(n Notation) string {
+	var sb strings.Builder
+
+	if e.Negative {
+		sb.WriteByte('-')
+	}
+	sb.WriteString(e.Int.Text(int(e.Base)))
+
+	if len(e.Prefix) == 0 && len(e.Repetend) == 0 {
+		return sb.String()
+	}
+
+	sb.WriteByte('.')
+	for _, d := range e.Prefix {
+		sb.WriteByte(digitChars[d])
+	}
+
+	switch n {
+	case VINCULUM:
+		for _, d := range e.Repetend {
+			sb.WriteByte(digitChars[d])
+			sb.WriteString("̅")
+		}
+
+	default:
+		if len(e.Repetend) > 0 {
+			sb.WriteByte('(')
+			for _, d := range e.Repetend {
+				sb.WriteByte(digitChars[d])
+			}
+			sb.WriteByte(')')
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,212 @@
+package num
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// EGYPTIAN FRACTIONS
+// A positive fraction is written as a sum of distinct unit fractions 1/d1 + 1/d2 + ... with
+// d1 < d2 < .... Searches for a fixed number of terms k are finite because the next denominator d
+// of the remainder a/b must satisfy b/a <= d <= k*b/a.
+
+// ErrNoEgyptian is returned when no decomposition exists within the requested number of terms
+var ErrNoEgyptian = errors.New("num: no Egyptian fraction decomposition within the term bound")
+
+// EgyptianGreedy returns the decomposition of f found by repeatedly taking the largest unit
+// fraction that fits, which always terminates but may produce very large denominators.
+func (f *Frac) EgyptianGreedy() ([]*Frac, error) {
+	a, b, err := egyptianTerms(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		res  []*Frac
+		prev = Int(0)
+	)
+
+	for a > 0 {
+		d := (b + a - 1) / a
+		if d <= prev {
+			d = prev + 1
+		}
+
+		if a, b, err = unitRemainder(a, b, d); err != nil {
+			return nil, err
+		}
+
+		res, prev = append(res, NewFrac(1, d)), d
+	}
+
+	return res, nil
+}
+
+// EgyptianShortest returns a decomposition of f with the fewest terms, up to maxTerms. Among
+// the shortest decompositions the one with the smallest largest denominator is returned.
+func (f *Frac) EgyptianShortest(maxTerms Int) ([]*Frac, error) {
+	a, b, err := egyptianTerms(f)
+	if err != nil {
+		return nil, err
+	}
+
+	for k := Int(1); k <= maxTerms; k++ {
+		if s := egyptianBest(a, b, k, 0); s != nil {
+			return unitFracs(s), nil
+		}
+	}
+
+	return nil, ErrNoEgyptian
+}
+
+// EgyptianMinMax returns a decomposition of f with the smallest possible largest denominator
+// using at most maxTerms terms. Ties are broken in favour of fewer terms.
+func (f *Frac) EgyptianMinMax(maxTerms Int) ([]*Frac, error) {
+	a, b, err := egyptianTerms(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var best Set
+	for k := Int(1); k <= maxTerms; k++ {
+		bound := Int(0)
+		if best != nil {
+			bound = best[len(best)-1] - 1
+		}
+
+		if s := egyptianBest(a, b, k, bound); s != nil {
+			best = s
+		}
+	}
+
+	if best == nil {
+		return nil, ErrNoEgyptian
+	}
+
+	return unitFracs(best), nil
+}
+
+// Egyptian returns a stream of every decomposition of f into exactly k distinct unit fractions,
+// in lexicographic order of their denominators. Branches whose intermediate values overflow an
+// Int are skipped.
+func (f *Frac) Egyptian(k Int) chan []*Frac {
+	c := make(chan []*Frac, 1)
+
+	go func() {
+		defer close(c)
+
+		a, b, err := egyptianTerms(f)
+		if err != nil || k < 1 {
+			return
+		}
+
+		e := &egyptian{fn: func(s Set) { c <- unitFracs(s) }}
+		e.search(a, b, k, 1)
+	}()
+
+	return c
+}
+
+// egyptian is a depth-first search over decompositions into a fixed number of terms
+type egyptian struct {
+	maxDen Int // largest denominator allowed, or 0 for no limit
+	dens   Set
+	fn     func(Set)
+}
+
+// search calls e.fn with every decomposition of a/b into k unit fractions whose denominators
+// are at least lo
+func (e *egyptian) search(a, b, k, lo Int) {
+	if k == 1 {
+		if d := b / a; b%a == 0 && d >= lo && (e.maxDen == 0 || d <= e.maxDen) {
+			e.fn(append(e.dens, d))
+		}
+		return
+	}
+
+	if d := (b + a - 1) / a; d > lo {
+		lo = d
+	}
+
+	hi, err := k.MulChecked(b)
+	if err != nil {
+		hi = math.MaxInt64
+	} else {
+		hi /= a
+	}
+
+	// The bound is re-read on each pass as a search for the best decomposition tightens it
+	for d := lo; d <= hi && (e.maxDen == 0 || d <= e.maxDen-k+1); d++ {
+		n, m, err := unitRemainder(a, b, d)
+		if err != nil || n <= 0 {
+			continue
+		}
+
+		e.dens = append(e.dens, d)
+		e.search(n, m, k-1, d+1)
+		e.dens = e.dens[:len(e.dens)-1]
+	}
+}
+
+// egyptianBest returns the denominators of the decomposition of a/b into k terms with the
+// smallest largest denominator, which must not exceed maxDen if it is non-zero
+func egyptianBest(a, b, k, maxDen Int) Set {
+	var (
+		best Set
+		e    = &egyptian{maxDen: maxDen}
+	)
+
+	e.fn = func(s Set) {
+		best = append(Set{}, s...)
+		e.maxDen = s[len(s)-1] - 1
+	}
+	e.search(a, b, k, 1)
+
+	return best
+}
+
+// egyptianTerms returns f in lowest terms, or an error if f is not positive
+func egyptianTerms(f *Frac) (Int, Int, error) {
+	g, err := newReduced(f.Num, f.Den)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if g.Num <= 0 {
+		return 0, 0, fmt.Errorf("Egyptian fractions require a positive fraction [%v]", f)
+	}
+
+	return g.Num, g.Den, nil
+}
+
+// unitRemainder returns a/b - 1/d in lowest terms, or ErrOverflow
+func unitRemainder(a, b, d Int) (Int, Int, error) {
+	ad, err := a.MulChecked(d)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	bd, err := b.MulChecked(d)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	n := ad - b
+	if n == 0 {
+		return 0, 1, nil
+	}
+
+	g := n.GCD(bd)
+	return n / g, bd / g, nil
+}
+
+// unitFracs returns the unit fractions with denominators in s
+func unitFracs(s Set) []*Frac {
+	res := make([]*Frac, len(s))
+	for i, d := range s {
+		res[i] = NewFrac(1, d)
+	}
+
+	return res
+}
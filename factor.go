@@ -0,0 +1,203 @@
+package num
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FACTORIZATION
+// Small factors are removed by trial division over a 2,3,5 wheel and whatever remains
+// is split with Pollard's rho (Brent's variant) until every part is prime.
+
+// trialLimit is the bound used for trial division before switching to Pollard's rho
+const trialLimit = 1 << 12
+
+// wheel holds the gaps between successive integers coprime to 2, 3 and 5 starting from 7
+var wheel = []uint64{4, 2, 4, 2, 4, 6, 2, 6}
+
+// PrimePower represents the prime power Prime^Exp
+type PrimePower struct {
+	Prime, Exp Int
+}
+
+// Factorization is the prime power decomposition of an Int ordered by ascending Prime
+type Factorization []PrimePower
+
+// Factorize returns the prime factorization of n. Values of n below 2 return an empty Factorization.
+func (n Int) Factorize() Factorization {
+	f := Factorization{}
+	if n < 2 {
+		return f
+	}
+
+	var (
+		m   = uint64(n)
+		pfs []uint64
+	)
+
+	for _, p := range []uint64{2, 3, 5} {
+		for m%p == 0 {
+			pfs, m = append(pfs, p), m/p
+		}
+	}
+
+	for p, i := uint64(7), 0; p < trialLimit && p*p <= m; p, i = p+wheel[i], (i+1)%len(wheel) {
+		for m%p == 0 {
+			pfs, m = append(pfs, p), m/p
+		}
+	}
+
+	// Any cofactor below trialLimit^2 has no factors left to find and must be prime
+	if m > 1 {
+		if m < trialLimit*trialLimit {
+			pfs = append(pfs, m)
+		} else {
+			pfs = append(pfs, splitFactors(m)...)
+		}
+	}
+
+	sort.Slice(pfs, func(i, j int) bool { return pfs[i] < pfs[j] })
+	for _, p := range pfs {
+		if last := len(f) - 1; last >= 0 && f[last].Prime == Int(p) {
+			f[last].Exp++
+		} else {
+			f = append(f, PrimePower{Prime: Int(p), Exp: 1})
+		}
+	}
+
+	return f
+}
+
+// splitFactors returns the (unordered, repeated) prime factors of n
+func splitFactors(n uint64) []uint64 {
+	if n == 1 {
+		return nil
+	}
+
+	if isPrime(n) {
+		return []uint64{n}
+	}
+
+	d := pollardBrent(n)
+	return append(splitFactors(d), splitFactors(n/d)...)
+}
+
+// pollardBrent returns a non-trivial factor of the odd composite n
+func pollardBrent(n uint64) uint64 {
+	if n%2 == 0 {
+		return 2
+	}
+
+	absDiff := func(a, b uint64) uint64 {
+		if a > b {
+			return a - b
+		}
+		return b - a
+	}
+
+	for c := uint64(1); ; c++ {
+		var (
+			f = func(v uint64) uint64 { return (mulMod(v, v, n) + c) % n }
+
+			x, ys      uint64
+			y, g, r, q = uint64(2), uint64(1), uint64(1), uint64(1)
+			m          = uint64(128)
+		)
+
+		for g == 1 {
+			x = y
+			for i := uint64(0); i < r; i++ {
+				y = f(y)
+			}
+
+			for k := uint64(0); k < r && g == 1; k += m {
+				ys = y
+				for i := uint64(0); i < m && i < r-k; i++ {
+					y = f(y)
+					q = mulMod(q, absDiff(x, y), n)
+				}
+				g = gcd64(q, n)
+			}
+
+			r <<= 1
+		}
+
+		// The batched product hit a multiple of n so step back through the last batch one at a time
+		if g == n {
+			for g = 1; g == 1; {
+				ys = f(ys)
+				g = gcd64(absDiff(x, ys), n)
+			}
+		}
+
+		if g != n {
+			return g
+		}
+	}
+}
+
+// gcd64 returns the Greatest Common Divisor of a and b
+func gcd64(a, b uint64) uint64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// Int returns the value that f is the factorization of
+func (f Factorization) Int() Int {
+	res := Int(1)
+
+	for _, pp := range f {
+		for i := Int(0); i < pp.Exp; i++ {
+			res *= pp.Prime
+		}
+	}
+
+	return res
+}
+
+// Primes returns the distinct primes of f in ascending order
+func (f Factorization) Primes() Set {
+	res := Set{}
+
+	for _, pp := range f {
+		res = append(res, pp.Prime)
+	}
+
+	return res
+}
+
+// Divisors returns every divisor of the value that f factorizes in ascending order
+func (f Factorization) Divisors() Set {
+	res := Set{1}
+
+	for _, pp := range f {
+		ln := len(res)
+		for i, pk := Int(1), Int(1); i <= pp.Exp; i++ {
+			pk *= pp.Prime
+			for _, d := range res[:ln] {
+				res = append(res, d*pk)
+			}
+		}
+	}
+
+	sort.Sort(res)
+	return res
+}
+
+// String returns f in the form 2^3 * 3 * 5^2 and satisfies the stringer interface
+func (f Factorization) String() string {
+	var parts []string
+
+	for _, pp := range f {
+		if pp.Exp == 1 {
+			parts = append(parts, pp.Prime.String())
+		} else {
+			parts = append(parts, fmt.Sprintf("%v^%v", pp.Prime, pp.Exp))
+		}
+	}
+
+	return strings.Join(parts, " * ")
+}
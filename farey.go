@@ -0,0 +1,196 @@
+package num
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// FAREY SEQUENCES
+// The length of F_n and the rank of a fraction within it are summatory functions over the
+// values floor(n/d), so both are found without enumerating the sequence. Sums of the totient
+// and Möbius functions up to n^(2/3) are tabulated with a sieve and the rest are recursed on.
+
+// FareyLen returns the number of terms in the nth Farey sequence, 1 + Phi(1) + ... + Phi(n).
+// The result is exact provided that it fits in an Int, which holds for n up to roughly 5e9.
+func FareyLen(n Int) Int {
+	if n < 1 {
+		return 0
+	}
+
+	// Halving before multiplying keeps the triangle numbers exact modulo 2^64
+	tri := func(v Int) Int {
+		if v%2 == 0 {
+			return v / 2 * (v + 1)
+		}
+		return (v + 1) / 2 * v
+	}
+
+	return 1 + newSummatory(n, tri, (*LinearSieve).Phi).sum(n)
+}
+
+// FareyNeighbours returns the terms either side of f in the nth Farey sequence. f must lie in
+// [0, 1] with a reduced denominator no greater than n. left is nil for 0/1 and right is nil for 1/1.
+func FareyNeighbours(f *Frac, n Int) (left, right *Frac, err error) {
+	g, err := newReduced(f.Num, f.Den)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	a, b := g.Num, g.Den
+	if a < 0 || a > b || b > n {
+		return nil, nil, fmt.Errorf("%v is not a term of the Farey sequence of order %d", f, n)
+	}
+
+	// The neighbours p/q < a/b < r/s satisfy a*q - b*p = 1 and b*r - a*s = 1 with q and s as
+	// large as possible
+	inv := Int(0)
+	if b > 1 {
+		if inv, err = a.ModInverse(b); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if a > 0 {
+		q := inv + (n-inv)/b*b
+		left = NewFrac(mulAddDiv(a, q, -1, b), q)
+	}
+
+	if a < b {
+		c := (b - inv) % b
+		s := c + (n-c)/b*b
+		right = NewFrac(mulAddDiv(a, s, 1, b), s)
+	}
+
+	return left, right, nil
+}
+
+// FareyRank returns the number of terms of the nth Farey sequence that are less than f, which
+// is the zero-based index of f when f is itself a term. Results are exact for n up to roughly 1e9.
+func FareyRank(f *Frac, n Int) (Int, error) {
+	g, err := newReduced(f.Num, f.Den)
+	if err != nil {
+		return 0, err
+	}
+
+	a, b := g.Num, g.Den
+	switch {
+	case n < 1 || a <= 0:
+		return 0, nil
+	case a > b:
+		return FareyLen(n), nil
+	}
+
+	// count(m) counts the pairs 0 < p/q < a/b with q <= m whether or not p/q is reduced, so the
+	// reduced pairs follow by Möbius inversion over the common factor
+	var (
+		count = func(m Int) Int { return floorSum(m, b, a, a-1) }
+		mert  = newSummatory(n, func(Int) Int { return 1 }, (*LinearSieve).Mu)
+		r     = Int(0)
+	)
+
+	for d := Int(1); d <= n; {
+		v := n / d
+		e := n / v
+		r += (mert.sum(e) - mert.sum(d-1)) * count(v)
+		d = e + 1
+	}
+
+	// 0/1 precedes every other term
+	return r + 1, nil
+}
+
+// summatory evaluates S(n) = f(1) + ... + f(n) for an arithmetic function f whose Dirichlet
+// convolution with 1 has the closed-form summatory function total
+type summatory struct {
+	small []Int // small[i] = S(i)
+	memo  map[Int]Int
+	total func(Int) Int
+}
+
+// newSummatory returns a summatory for values up to n, sieving f below about n^(2/3)
+func newSummatory(n Int, total func(Int) Int, f func(*LinearSieve, Int) Int) *summatory {
+	lim := Int(math.Cbrt(float64(n)))
+	lim = lim*lim + 1
+	if lim > n {
+		lim = n
+	}
+	if lim < 1 {
+		lim = 1
+	}
+
+	var (
+		s  = &summatory{small: make([]Int, lim+1), memo: make(map[Int]Int), total: total}
+		ls = NewLinearSieve(lim + 1)
+	)
+
+	for i := Int(1); i <= lim; i++ {
+		s.small[i] = s.small[i-1] + f(ls, i)
+	}
+
+	return s
+}
+
+// sum returns S(v) using total(v) = S(v/1) + S(v/2) + ... + S(v/v)
+func (s *summatory) sum(v Int) Int {
+	if v < Int(len(s.small)) {
+		return s.small[v]
+	}
+
+	if r, ok := s.memo[v]; ok {
+		return r
+	}
+
+	r := s.total(v)
+	for d := Int(2); d <= v; {
+		q := v / d
+		e := v / q
+		r -= (e - d + 1) * s.sum(q)
+		d = e + 1
+	}
+
+	s.memo[v] = r
+	return r
+}
+
+// floorSum returns the sum of floor((a*i + b) / m) for 0 <= i < n, where a and b are non-negative
+func floorSum(n, m, a, b Int) Int {
+	r := Int(0)
+
+	for {
+		if a >= m {
+			r += n * (n - 1) / 2 * (a / m)
+			a %= m
+		}
+
+		if b >= m {
+			r += n * (b / m)
+			b %= m
+		}
+
+		y := a*n + b
+		if y < m {
+			return r
+		}
+
+		n, b, m, a = y/m, y%m, a, m
+	}
+}
+
+// mulAddDiv returns (x*y + c) / m for non-negative x and y where c is -1 or 1, computing the
+// product in 128 bits. The quotient must fit in an Int.
+func mulAddDiv(x, y, c, m Int) Int {
+	hi, lo := bits.Mul64(uint64(x), uint64(y))
+
+	var carry uint64
+	if c < 0 {
+		lo, carry = bits.Sub64(lo, 1, 0)
+		hi -= carry
+	} else {
+		lo, carry = bits.Add64(lo, 1, 0)
+		hi += carry
+	}
+
+	q, _ := bits.Div64(hi, lo, uint64(m))
+	return Int(q)
+}
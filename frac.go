@@ -1,10 +1,10 @@
 package num
 
 import (
+	"errors"
 	"fmt"
 	"math"
-	"strconv"
-	"strings"
+	"math/big"
 )
 
 // Frac represents a (possibly mixed) fraction by its Int(eger), Num(erator), Den(ominator)
@@ -22,8 +22,10 @@ func NewFrac(n, d Int) *Frac {
 		Flt: float64(n) / float64(d),
 	}
 
-	i, _ := math.Modf(f.Flt)
-	f.Int = Int(i)
+	// Integer division truncates like math.Modf but stays exact beyond 2^53
+	if d != 0 {
+		f.Int = n / d
+	}
 
 	return f
 }
@@ -37,12 +39,16 @@ func (f *Frac) String() string {
 	return fmt.Sprintf("%v/%v", f.Num, f.Den)
 }
 
-// Reduce simplifies f and returns it
+// Reduce simplifies f, moving any sign into the numerator, and returns it
 func (f *Frac) Reduce() *Frac {
-	gcd := f.GCD()
+	if f.Den < 0 {
+		f.Num, f.Den = -f.Num, -f.Den
+	}
 
-	f.Num /= gcd
-	f.Den /= gcd
+	if gcd := f.GCD(); gcd != 0 {
+		f.Num /= gcd
+		f.Den /= gcd
+	}
 
 	return f
 }
@@ -52,15 +58,103 @@ func (f *Frac) Inverse() *Frac {
 	return NewFrac(f.Den, f.Num)
 }
 
-// FloatToFrac converts a decimal to a reduced fraction
+// FloatToFrac converts f to a reduced fraction equal to its exact binary value, so 0.1 becomes
+// 3602879701896397/36028797018963968; use BestApproximation for the simplest nearby fraction.
+// Values whose denominator does not fit in an Int return their best approximation instead,
+// and values outside the range of Int (including NaN and Inf) return nil.
 func FloatToFrac(f float64) *Frac {
-	_, fr := math.Modf(f)
+	r := FloatToRat(f)
+	if r == nil {
+		return nil
+	}
+
+	if fr, err := RatToBigFrac(r).Frac(); err == nil {
+		return fr
+	}
+
+	return BestRatApproximation(r, math.MaxInt64)
+}
+
+// FloatToRat returns the exact value of f as a big.Rat, or nil if f is NaN or Inf
+func FloatToRat(f float64) *big.Rat {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil
+	}
+
+	return new(big.Rat).SetFloat64(f)
+}
+
+// BestApproximation returns the fraction closest to x with a denominator no greater than maxDen,
+// as Python's Fraction.limit_denominator does. It returns nil if x is NaN, Inf or outside the
+// range of Int.
+func BestApproximation(x float64, maxDen Int) *Frac {
+	r := FloatToRat(x)
+	if r == nil {
+		return nil
+	}
+
+	return BestRatApproximation(r, maxDen)
+}
+
+// BestRatApproximation returns the fraction closest to x with a denominator no greater than maxDen
+// using the convergents and semiconvergents of the continued fraction of x. It returns nil if
+// maxDen < 1 or the result does not fit in a Frac.
+func BestRatApproximation(x *big.Rat, maxDen Int) *Frac {
+	if maxDen < 1 {
+		return nil
+	}
+
+	var (
+		limit = big.NewInt(int64(maxDen))
+		res   *big.Rat
+	)
+
+	if x.Denom().Cmp(limit) <= 0 {
+		res = x
+	} else {
+		var (
+			p0, q0, p1, q1 = big.NewInt(0), big.NewInt(1), big.NewInt(1), big.NewInt(0)
+			n, d           = new(big.Int).Set(x.Num()), new(big.Int).Set(x.Denom())
+			a, t           = new(big.Int), new(big.Int)
+		)
+
+		for {
+			a.Div(n, d)
+
+			// Stop before the next convergent's denominator exceeds the limit
+			q2 := new(big.Int).Add(q0, t.Mul(a, q1))
+			if q2.Cmp(limit) > 0 {
+				break
+			}
+
+			p2 := new(big.Int).Add(p0, t.Mul(a, p1))
+			p0, q0, p1, q1 = p1, q1, p2, q2
+			n, d = d, new(big.Int).Sub(n, t.Mul(a, d))
+		}
+
+		// The best semiconvergent below the limit competes with the last convergent
+		k := new(big.Int).Div(t.Sub(limit, q0), q1)
+		semi := new(big.Rat).SetFrac(
+			new(big.Int).Add(p0, new(big.Int).Mul(k, p1)),
+			new(big.Int).Add(q0, new(big.Int).Mul(k, q1)),
+		)
+		conv := new(big.Rat).SetFrac(p1, q1)
 
-	//fmt.Println(fr)
-	d := math.Pow10(decimalPlaces(fr))
-	n := d * fr
+		ds := new(big.Rat).Sub(semi, x)
+		dc := new(big.Rat).Sub(conv, x)
+		if dc.Abs(dc).Cmp(ds.Abs(ds)) <= 0 {
+			res = conv
+		} else {
+			res = semi
+		}
+	}
+
+	f, err := RatToBigFrac(res).Frac()
+	if err != nil {
+		return nil
+	}
 
-	return NewFrac(Int(n), Int(d)).Reduce()
+	return f
 }
 
 // CF emits the Continued Fraction integer terms of f using Euclid's algorithm.
@@ -84,9 +178,198 @@ func (f *Frac) CF() Set {
 	return res[1:]
 }
 
-// decimalPlaces returns the number of decimal places in f
-func decimalPlaces(f float64) int {
-	str := strconv.FormatFloat(f, 'f', -1, 64)
-	arr := strings.Split(str, ".")
-	return len(arr[1])
+// ErrZeroDenominator is returned when an operation would produce a fraction with a zero denominator
+var ErrZeroDenominator = errors.New("num: zero denominator")
+
+// newReduced returns n/d in lowest terms with the sign carried by the numerator
+func newReduced(n, d Int) (*Frac, error) {
+	if d == 0 {
+		return nil, ErrZeroDenominator
+	}
+
+	if d < 0 {
+		if n == math.MinInt64 || d == math.MinInt64 {
+			return nil, ErrOverflow
+		}
+		n, d = -n, -d
+	}
+
+	g := n.GCD(d)
+	return NewFrac(n/g, d/g), nil
+}
+
+// Sign returns -1, 0 or 1 according to the sign of f
+func (f *Frac) Sign() Int {
+	switch {
+	case f.Num == 0:
+		return 0
+	case (f.Num < 0) != (f.Den < 0):
+		return -1
+	}
+	return 1
+}
+
+// Cmp returns -1, 0 or 1 as f is less than, equal to or greater than g. The comparison is exact.
+func (f *Frac) Cmp(g *Frac) int {
+	// Compare f.Num*g.Den with g.Num*f.Den, flipping for each negative denominator
+	a := new(big.Int).Mul(big.NewInt(int64(f.Num)), big.NewInt(int64(g.Den)))
+	b := new(big.Int).Mul(big.NewInt(int64(g.Num)), big.NewInt(int64(f.Den)))
+
+	c := a.Cmp(b)
+	if (f.Den < 0) != (g.Den < 0) {
+		c = -c
+	}
+	return c
+}
+
+// Equal returns true if f and g represent the same value
+func (f *Frac) Equal(g *Frac) bool {
+	return f.Cmp(g) == 0
+}
+
+// Add returns f+g as a new reduced Frac, or ErrOverflow
+func (f *Frac) Add(g *Frac) (*Frac, error) {
+	if f.Den == 0 || g.Den == 0 {
+		return nil, ErrZeroDenominator
+	}
+
+	// a/b + c/d = (a*(d/k) + c*(b/k)) / (b/k*d) where k = gcd(b, d)
+	k := f.Den.GCD(g.Den)
+
+	x, err := f.Num.MulChecked(g.Den / k)
+	if err != nil {
+		return nil, err
+	}
+
+	y, err := g.Num.MulChecked(f.Den / k)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := x.AddChecked(y)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := (f.Den / k).MulChecked(g.Den)
+	if err != nil {
+		return nil, err
+	}
+
+	return newReduced(n, d)
+}
+
+// Sub returns f-g as a new reduced Frac, or ErrOverflow
+func (f *Frac) Sub(g *Frac) (*Frac, error) {
+	if g.Num == math.MinInt64 {
+		return nil, ErrOverflow
+	}
+
+	return f.Add(&Frac{Num: -g.Num, Den: g.Den})
+}
+
+// Mul returns f*g as a new reduced Frac, or ErrOverflow
+func (f *Frac) Mul(g *Frac) (*Frac, error) {
+	if f.Den == 0 || g.Den == 0 {
+		return nil, ErrZeroDenominator
+	}
+
+	// Cancel common factors across the product first to keep the intermediates small
+	k1, k2 := f.Num.GCD(g.Den), g.Num.GCD(f.Den)
+	if k1 == 0 {
+		k1 = 1
+	}
+	if k2 == 0 {
+		k2 = 1
+	}
+
+	n, err := (f.Num / k1).MulChecked(g.Num / k2)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := (f.Den / k2).MulChecked(g.Den / k1)
+	if err != nil {
+		return nil, err
+	}
+
+	return newReduced(n, d)
+}
+
+// Div returns f/g as a new reduced Frac, or an error if g is 0 or the result overflows
+func (f *Frac) Div(g *Frac) (*Frac, error) {
+	if g.Num == 0 {
+		return nil, ErrZeroDenominator
+	}
+
+	return f.Mul(&Frac{Num: g.Den, Den: g.Num})
 }
+
+// Pow returns f^k as a new reduced Frac, or an error if it overflows. A negative k
+// raises the inverse of f.
+func (f *Frac) Pow(k Int) (*Frac, error) {
+	b, err := newReduced(f.Num, f.Den)
+	if err != nil {
+		return nil, err
+	}
+
+	if k < 0 {
+		if b, err = newReduced(b.Den, b.Num); err != nil {
+			return nil, err
+		}
+		k = -k
+	}
+
+	// Powers of 0 and 1 are fixed and those of -1 alternate, so only these may have a large k
+	switch {
+	case k == 0:
+		return NewFrac(1, 1), nil
+	case b.Num == 0 || b.Num == b.Den:
+		return b, nil
+	case b.Num == -b.Den:
+		return NewFrac(1-2*(k%2), 1), nil
+	}
+
+	// b is already reduced so its powers are too
+	n, err := powChecked(b.Num, k)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := powChecked(b.Den, k)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFrac(n, d), nil
+}
+
+// powChecked returns n^k for k >= 0 by repeated squaring, or ErrOverflow
+func powChecked(n, k Int) (Int, error) {
+	res := Int(1)
+
+	for ; k > 0; k >>= 1 {
+		var err error
+		if k&1 == 1 {
+			if res, err = res.MulChecked(n); err != nil {
+				return 0, err
+			}
+		}
+
+		// The final square is never used, so it must not report an overflow
+		if k > 1 {
+			if n, err = n.MulChecked(n); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// FracSet is a slice of *Frac that can be sorted exactly by value
+type FracSet []*Frac
+
+func (s FracSet) Len() int           { return len(s) }
+func (s FracSet) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s FracSet) Less(i, j int) bool { return s[i].Cmp(s[j]) < 0 }
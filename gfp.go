@@ -0,0 +1,231 @@
+package num
+
+import (
+	"errors"
+	"fmt"
+)
+
+// LINEAR ALGEBRA OVER GF(p)
+// Gauss-Jordan elimination with every element reduced into [0, p). Pivots are inverted with
+// ModInverse, which requires p to be prime. Over GF(2) rows are packed 64 columns to a word and
+// eliminated with XOR. A consistent system has p^(columns - rank) solutions, each of which is
+// the particular solution from SolveMod plus a combination of the NullspaceMod basis.
+
+// ErrInconsistent is returned when a linear system has no solution
+var ErrInconsistent = errors.New("num: linear system has no solution")
+
+// EchelonMod returns the reduced row echelon form of m over GF(p) and its rank
+func (m Matrix) EchelonMod(p Int) (Matrix, Int, error) {
+	a, piv, err := m.rrefMod("EchelonMod", p, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return a, Int(len(piv)), nil
+}
+
+// RankMod returns the rank of m over GF(p)
+func (m Matrix) RankMod(p Int) (Int, error) {
+	_, piv, err := m.rrefMod("RankMod", p, nil)
+	return Int(len(piv)), err
+}
+
+// NullspaceMod returns a basis of the solutions of m*x = 0 over GF(p), one vector per row
+func (m Matrix) NullspaceMod(p Int) (Matrix, error) {
+	a, piv, err := m.rrefMod("NullspaceMod", p, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		cols  = m.dims()[1]
+		pivot = make(map[int]bool)
+		res   = Matrix{}
+	)
+
+	for _, c := range piv {
+		pivot[c] = true
+	}
+
+	// Each free column gives one basis vector with the pivot variables solved for
+	for f := 0; f < int(cols); f++ {
+		if pivot[f] {
+			continue
+		}
+
+		v := make(Set, cols)
+		v[f] = 1
+		for i, c := range piv {
+			v[c] = (p - a[i][f]) % p
+		}
+		res = append(res, v)
+	}
+
+	return res, nil
+}
+
+// SolveMod returns a solution x of m*x = b over GF(p) with every free variable set to 0, or
+// ErrInconsistent if there is none
+func (m Matrix) SolveMod(b Set, p Int) (Set, error) {
+	if d := m.dims(); d[1] >= 0 && Int(len(b)) != d[0] {
+		return nil, &DimensionError{Op: "SolveMod", Dims: [][2]Int{d, {Int(len(b)), 1}}}
+	}
+
+	a, piv, err := m.rrefMod("SolveMod", p, b)
+	if err != nil || len(a) == 0 {
+		return Set{}, err
+	}
+
+	var (
+		cols = len(a[0]) - 1
+		x    = make(Set, cols)
+	)
+
+	// Rows below the rank have no pivot, so a non-zero right hand side there is a contradiction
+	for i := len(piv); i < len(a); i++ {
+		if a[i][cols] != 0 {
+			return nil, ErrInconsistent
+		}
+	}
+
+	for i, c := range piv {
+		x[c] = a[i][cols]
+	}
+
+	return x, nil
+}
+
+// rrefMod returns the reduced row echelon form of m, augmented with b if it is not nil, over
+// GF(p) along with the pivot column of each leading row. Only the columns of m are eliminated.
+func (m Matrix) rrefMod(op string, p Int, b Set) (Matrix, []int, error) {
+	d := m.dims()
+	if d[1] < 0 {
+		return nil, nil, &DimensionError{Op: op, Dims: [][2]Int{d}}
+	}
+
+	if !p.Is(PRIME) {
+		return nil, nil, fmt.Errorf("Matrix %s requires a prime modulus [%d]", op, p)
+	}
+
+	w := int(d[1])
+	if b != nil {
+		w++
+	}
+
+	a := NewMatrix(d[0], Int(w))
+	for i, row := range m {
+		for j, v := range row {
+			a[i][j] = Int(v.reduce(p))
+		}
+		if b != nil {
+			a[i][w-1] = Int(b[i].reduce(p))
+		}
+	}
+
+	if p == 2 {
+		piv := rref2(a, int(d[1]))
+		return a, piv, nil
+	}
+
+	var (
+		piv []int
+		r   = 0
+	)
+
+	for c := 0; c < int(d[1]) && r < len(a); c++ {
+		k := r
+		for k < len(a) && a[k][c] == 0 {
+			k++
+		}
+
+		if k == len(a) {
+			continue
+		}
+		a[k], a[r] = a[r], a[k]
+
+		inv, err := a[r][c].ModInverse(p)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for j := c; j < w; j++ {
+			a[r][j] = a[r][j].ModMul(inv, p)
+		}
+
+		for i := range a {
+			if i == r || a[i][c] == 0 {
+				continue
+			}
+
+			f := a[i][c]
+			for j := c; j < w; j++ {
+				if t := f.ModMul(a[r][j], p); a[i][j] >= t {
+					a[i][j] -= t
+				} else {
+					a[i][j] += p - t
+				}
+			}
+		}
+
+		piv = append(piv, c)
+		r++
+	}
+
+	return a, piv, nil
+}
+
+// rref2 reduces a, whose elements are 0 or 1, to reduced row echelon form over GF(2) in place
+// by packing each row into words and returns the pivot column of each leading row. Only the
+// first cols columns are eliminated.
+func rref2(a Matrix, cols int) []int {
+	if len(a) == 0 {
+		return nil
+	}
+
+	var (
+		w    = len(a[0])
+		bits = make([][]uint64, len(a))
+		piv  []int
+		r    = 0
+	)
+
+	for i, row := range a {
+		bits[i] = make([]uint64, (w+63)/64)
+		for j, v := range row {
+			bits[i][j/64] |= uint64(v) << uint(j%64)
+		}
+	}
+
+	for c := 0; c < cols && r < len(bits); c++ {
+		word, bit := c/64, uint64(1)<<uint(c%64)
+
+		k := r
+		for k < len(bits) && bits[k][word]&bit == 0 {
+			k++
+		}
+
+		if k == len(bits) {
+			continue
+		}
+		bits[k], bits[r] = bits[r], bits[k]
+
+		for i := range bits {
+			if i != r && bits[i][word]&bit != 0 {
+				for j := word; j < len(bits[i]); j++ {
+					bits[i][j] ^= bits[r][j]
+				}
+			}
+		}
+
+		piv = append(piv, c)
+		r++
+	}
+
+	for i, row := range bits {
+		for j := range a[i] {
+			a[i][j] = Int(row[j/64] >> uint(j%64) & 1)
+		}
+	}
+
+	return piv
+}
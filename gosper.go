@@ -0,0 +1,240 @@
+package num
+
+import (
+	"math/big"
+)
+
+// CONTINUED FRACTION ARITHMETIC
+// Gosper's algorithm evaluates z = (axy + bx + cy + d) / (exy + fx + gy + h) term by term from
+// the continued fractions of x and y. Input terms are consumed until every value z can take for
+// the remaining tails agrees on its integer part, which is then emitted. Results that are
+// rational but reached from irrational inputs (such as sqrt(2)*sqrt(2)) never settle on a term,
+// so consuming them blocks forever.
+
+// gosper holds the state of the bihomographic transform
+type gosper struct {
+	x, y   *CFStream
+	xLive  bool // x has terms left
+	yLive  bool // y has terms left
+	xFirst bool // the leading term of x is still to be consumed
+	yFirst bool // the leading term of y is still to be consumed
+	turn   bool // alternates which input is consumed next
+	k      [8]*big.Int
+}
+
+// Bihomographic returns the continued fraction of (axy + bx + cy + d) / (exy + fx + gy + h).
+// Both x and y are consumed.
+func Bihomographic(x, y *CFStream, a, b, c, d, e, f, g, h Int) *CFStream {
+	gs := &gosper{x: x, y: y, xLive: true, yLive: true, xFirst: true, yFirst: true}
+	for i, v := range []Int{a, b, c, d, e, f, g, h} {
+		gs.k[i] = big.NewInt(int64(v))
+	}
+
+	return NewCFStream(gs.next)
+}
+
+// Homographic returns the continued fraction of the Möbius transform (ax + b) / (cx + d). x is consumed.
+func Homographic(x *CFStream, a, b, c, d Int) *CFStream {
+	// With y exhausted from the outset only the b, d, f and h coefficients are used
+	gs := &gosper{x: x, xLive: true, xFirst: true}
+	for i, v := range []Int{0, a, 0, b, 0, c, 0, d} {
+		gs.k[i] = big.NewInt(int64(v))
+	}
+
+	return NewCFStream(gs.next)
+}
+
+// Add returns the continued fraction of x + y. Both x and y are consumed.
+func (x *CFStream) Add(y *CFStream) *CFStream {
+	return Bihomographic(x, y, 0, 1, 1, 0, 0, 0, 0, 1)
+}
+
+// Sub returns the continued fraction of x - y. Both x and y are consumed.
+func (x *CFStream) Sub(y *CFStream) *CFStream {
+	return Bihomographic(x, y, 0, 1, -1, 0, 0, 0, 0, 1)
+}
+
+// Mul returns the continued fraction of x * y. Both x and y are consumed.
+func (x *CFStream) Mul(y *CFStream) *CFStream {
+	return Bihomographic(x, y, 1, 0, 0, 0, 0, 0, 0, 1)
+}
+
+// Div returns the continued fraction of x / y. Both x and y are consumed.
+func (x *CFStream) Div(y *CFStream) *CFStream {
+	return Bihomographic(x, y, 0, 1, 0, 0, 0, 0, 1, 0)
+}
+
+// FracCF returns a CFStream of the terms of f, including the leading integer term. Terms are
+// formed with floor division so that every term after the first is positive.
+func FracCF(f *Frac) *CFStream {
+	n, d := f.Num, f.Den
+	if d < 0 {
+		n, d = -n, -d
+	}
+
+	return NewCFStream(func() (Int, bool) {
+		if d == 0 {
+			return 0, false
+		}
+
+		a := floorDiv(n, d)
+		n, d = d, n-a*d
+		return a, true
+	})
+}
+
+// ChanCF returns a CFStream of the integer terms emitted on c, such as the channel returned by
+// ContinuedFraction. Gosper arithmetic assumes every term after the first is positive, which
+// ContinuedFraction only guarantees for non-negative values.
+func ChanCF(c chan CF) *CFStream {
+	return NewCFStream(func() (Int, bool) {
+		t, ok := <-c
+		return t.Int, ok
+	})
+}
+
+// TermsCF returns a CFStream of the terms emitted on c, such as the channel returned by CFStream.Terms
+func TermsCF(c chan Int) *CFStream {
+	return NewCFStream(func() (Int, bool) {
+		t, ok := <-c
+		return t, ok
+	})
+}
+
+// next returns the next term of z
+func (gs *gosper) next() (Int, bool) {
+	k := &gs.k
+
+	for {
+		// Both inputs need their leading term consumed so that the tails lie in [1, inf]
+		switch {
+		case gs.xLive && gs.xFirst:
+			gs.xFirst = false
+			gs.ingestX()
+			continue
+		case gs.yLive && gs.yFirst:
+			gs.yFirst = false
+			gs.ingestY()
+			continue
+		}
+
+		// The coefficients that still depend on the live inputs give the corners of z
+		var idx []int
+		switch {
+		case gs.xLive && gs.yLive:
+			idx = []int{0, 1, 2, 3}
+		case gs.xLive:
+			idx = []int{1, 3}
+		case gs.yLive:
+			idx = []int{2, 3}
+		default:
+			idx = []int{3}
+		}
+
+		if r, ok, done := gs.corners(idx); done {
+			return 0, false
+		} else if ok {
+			// z -> 1/(z - r)
+			for i := 0; i < 4; i++ {
+				k[i], k[i+4] = k[i+4], new(big.Int).Sub(k[i], new(big.Int).Mul(r, k[i+4]))
+			}
+			return Int(r.Int64()), true
+		}
+
+		gs.turn = !gs.turn
+		if gs.xLive && (gs.turn || !gs.yLive) {
+			gs.ingestX()
+		} else {
+			gs.ingestY()
+		}
+	}
+}
+
+// corners returns the common floor of the ratios k[i]/k[i+4] for each i in idx if they agree and
+// the denominator cannot vanish, and reports done if z has no terms left
+func (gs *gosper) corners(idx []int) (r *big.Int, ok, done bool) {
+	zero, sign := true, 0
+
+	for _, i := range idx {
+		s := gs.k[i+4].Sign()
+		if s != 0 {
+			zero = false
+		}
+
+		switch {
+		case s == 0:
+			sign = 2
+		case sign == 0:
+			sign = s
+		case sign != s:
+			sign = 2
+		}
+	}
+
+	if zero {
+		return nil, false, true
+	}
+
+	if sign == 2 {
+		return nil, false, false
+	}
+
+	for _, i := range idx {
+		q := floorRat(new(big.Rat).SetFrac(gs.k[i], gs.k[i+4]))
+		if r == nil {
+			r = q
+		} else if r.Cmp(q) != 0 {
+			return nil, false, false
+		}
+	}
+
+	return r, true, false
+}
+
+// ingestX consumes the next term p of x, substituting x = p + 1/x'
+func (gs *gosper) ingestX() {
+	k := &gs.k
+
+	p, ok := gs.x.Next()
+	if !ok {
+		// x = inf leaves z = (ay + b) / (ey + f)
+		gs.xLive = false
+		*k = [8]*big.Int{new(big.Int), new(big.Int), k[0], k[1], new(big.Int), new(big.Int), k[4], k[5]}
+		return
+	}
+
+	bp := big.NewInt(int64(p))
+	for _, o := range []int{0, 4} {
+		a, b, c, d := k[o], k[o+1], k[o+2], k[o+3]
+		k[o] = new(big.Int).Add(new(big.Int).Mul(a, bp), c)
+		k[o+1] = new(big.Int).Add(new(big.Int).Mul(b, bp), d)
+		k[o+2], k[o+3] = a, b
+	}
+}
+
+// ingestY consumes the next term q of y, substituting y = q + 1/y'
+func (gs *gosper) ingestY() {
+	k := &gs.k
+
+	if gs.y == nil {
+		gs.yLive = false
+		return
+	}
+
+	q, ok := gs.y.Next()
+	if !ok {
+		// y = inf leaves z = (ax + c) / (ex + g)
+		gs.yLive = false
+		*k = [8]*big.Int{new(big.Int), k[0], new(big.Int), k[2], new(big.Int), k[4], new(big.Int), k[6]}
+		return
+	}
+
+	bq := big.NewInt(int64(q))
+	for _, o := range []int{0, 4} {
+		a, b, c, d := k[o], k[o+1], k[o+2], k[o+3]
+		k[o] = new(big.Int).Add(new(big.Int).Mul(a, bq), b)
+		k[o+1] = a
+		k[o+2] = new(big.Int).Add(new(big.Int).Mul(c, bq), d)
+		k[o+3] = c
+	}
+}
@@ -3,7 +3,6 @@ package num
 import (
 	"fmt"
 	"math"
-	"math/big"
 )
 
 // T represents IDs of numerical types
@@ -34,7 +33,7 @@ func (n Int) Is(t T) bool {
 		return n%2 != 0
 
 	case PRIME:
-		return big.NewInt(int64(n)).ProbablyPrime(5)
+		return n > 1 && isPrime(uint64(n))
 
 	case TRIANGLE:
 		i, f := math.Modf((math.Sqrt(float64(8*n+1)) - 1) / 2)
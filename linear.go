@@ -0,0 +1,315 @@
+package num
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// EXACT LINEAR ALGEBRA
+// Det and Rank use Bareiss' fraction-free elimination, in which every intermediate value is a
+// minor of the original matrix. The elimination is first run on Ints with checked arithmetic and
+// is only repeated with big.Ints if a product overflows. Inverse and Solve work over the
+// rationals and return exact BigFracs.
+
+// SingularError is returned when an operation requires a non-singular matrix
+type SingularError struct {
+	Op   string // the operation attempted
+	Size Int    // the number of rows of the matrix
+	Rank Int    // the rank of the matrix
+}
+
+func (e *SingularError) Error() string {
+	return fmt.Sprintf("Matrix %s: singular %dx%d matrix of rank %d", e.Op, e.Size, e.Size, e.Rank)
+}
+
+// bigRows returns a copy of m as rows of big.Ints
+func (m Matrix) bigRows() [][]*big.Int {
+	a := make([][]*big.Int, len(m))
+	for i, row := range m {
+		a[i] = make([]*big.Int, len(row))
+		for j, v := range row {
+			a[i][j] = big.NewInt(int64(v))
+		}
+	}
+
+	return a
+}
+
+// bareiss reduces a to row echelon form in place using fraction-free elimination and returns
+// its rank along with the sign of the row permutation used
+func bareiss(a [][]*big.Int) (rank Int, sign int) {
+	var (
+		prev = big.NewInt(1)
+		t    = new(big.Int)
+		r    = 0
+	)
+
+	sign = 1
+	if len(a) == 0 {
+		return 0, sign
+	}
+
+	for c := 0; c < len(a[0]) && r < len(a); c++ {
+		p := r
+		for p < len(a) && a[p][c].Sign() == 0 {
+			p++
+		}
+
+		if p == len(a) {
+			continue
+		}
+
+		if p != r {
+			a[p], a[r] = a[r], a[p]
+			sign = -sign
+		}
+
+		// a[i][j] = (a[i][j]*a[r][c] - a[i][c]*a[r][j]) / prev, which is always exact
+		for i := r + 1; i < len(a); i++ {
+			for j := c + 1; j < len(a[i]); j++ {
+				v := new(big.Int).Mul(a[i][j], a[r][c])
+				v.Sub(v, t.Mul(a[i][c], a[r][j]))
+				a[i][j] = v.Quo(v, prev)
+			}
+			a[i][c] = new(big.Int)
+		}
+
+		prev = a[r][c]
+		r++
+	}
+
+	return Int(r), sign
+}
+
+// bareiss64 is bareiss over Ints, returning ErrOverflow if an intermediate value does not fit
+func bareiss64(a Matrix) (rank Int, sign int, err error) {
+	var (
+		prev = Int(1)
+		r    = 0
+	)
+
+	sign = 1
+	if len(a) == 0 {
+		return 0, sign, nil
+	}
+
+	for c := 0; c < len(a[0]) && r < len(a); c++ {
+		p := r
+		for p < len(a) && a[p][c] == 0 {
+			p++
+		}
+
+		if p == len(a) {
+			continue
+		}
+
+		if p != r {
+			a[p], a[r] = a[r], a[p]
+			sign = -sign
+		}
+
+		for i := r + 1; i < len(a); i++ {
+			for j := c + 1; j < len(a[i]); j++ {
+				v, err := products(Set{a[i][j], a[r][c]}, Set{a[i][c], a[r][j]})
+				if err != nil {
+					return 0, 0, err
+				}
+
+				d, err := v[0].SubChecked(v[1])
+				if err != nil {
+					return 0, 0, err
+				}
+				a[i][j] = d / prev
+			}
+			a[i][c] = 0
+		}
+
+		prev = a[r][c]
+		r++
+	}
+
+	return Int(r), sign, nil
+}
+
+// echelon returns the rank of m and, if m is square, its determinant, which is otherwise nil.
+// Ints are used unless the elimination overflows.
+func (m Matrix) echelon() (Int, *big.Int) {
+	var (
+		n   = len(m)
+		det = func(r Int, sign int, last func() *big.Int) *big.Int {
+			switch {
+			case m.dims()[1] != Int(n):
+				return nil
+			case r < Int(n):
+				return new(big.Int)
+			case sign < 0:
+				return new(big.Int).Neg(last())
+			}
+			return last()
+		}
+	)
+
+	if n == 0 {
+		return 0, big.NewInt(1)
+	}
+
+	a := make(Matrix, n)
+	for i, row := range m {
+		a[i] = append(Set{}, row...)
+	}
+
+	if r, sign, err := bareiss64(a); err == nil {
+		return r, det(r, sign, func() *big.Int { return big.NewInt(int64(a[n-1][n-1])) })
+	}
+
+	b := m.bigRows()
+	r, sign := bareiss(b)
+	return r, det(r, sign, func() *big.Int { return new(big.Int).Set(b[n-1][n-1]) })
+}
+
+// BigDet returns the determinant of the square matrix m
+func (m Matrix) BigDet() (*big.Int, error) {
+	if _, err := m.square("Det"); err != nil {
+		return nil, err
+	}
+
+	_, d := m.echelon()
+	return d, nil
+}
+
+// Det returns the determinant of the square matrix m, or ErrOverflow if it does not fit in an Int
+func (m Matrix) Det() (Int, error) {
+	d, err := m.BigDet()
+	if err != nil {
+		return 0, err
+	}
+
+	if !d.IsInt64() {
+		return 0, ErrOverflow
+	}
+
+	return Int(d.Int64()), nil
+}
+
+// Rank returns the rank of m
+func (m Matrix) Rank() (Int, error) {
+	if d := m.dims(); d[1] < 0 {
+		return 0, &DimensionError{Op: "Rank", Dims: [][2]Int{d}}
+	}
+
+	r, _ := m.echelon()
+	return r, nil
+}
+
+// Inverse returns the exact inverse of the square matrix m, or a SingularError
+func (m Matrix) Inverse() ([][]*BigFrac, error) {
+	n, err := m.square("Inverse")
+	if err != nil {
+		return nil, err
+	}
+
+	if n == 0 {
+		return [][]*BigFrac{}, nil
+	}
+
+	x, err := m.gaussJordan("Inverse", Identity(n))
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([][]*BigFrac, n)
+	for i, row := range x {
+		res[i] = make([]*BigFrac, n)
+		for j, v := range row {
+			res[i][j] = RatToBigFrac(v)
+		}
+	}
+
+	return res, nil
+}
+
+// Solve returns the exact solution x of m*x = b for a square matrix m, or a SingularError if
+// the solution is not unique
+func (m Matrix) Solve(b Set) ([]*BigFrac, error) {
+	n, err := m.square("Solve")
+	if err != nil {
+		return nil, err
+	}
+
+	if Int(len(b)) != n {
+		return nil, &DimensionError{Op: "Solve", Dims: [][2]Int{{n, n}, {Int(len(b)), 1}}}
+	}
+
+	if n == 0 {
+		return []*BigFrac{}, nil
+	}
+
+	col := NewMatrix(n, 1)
+	for i, v := range b {
+		col[i][0] = v
+	}
+
+	x, err := m.gaussJordan("Solve", col)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*BigFrac, n)
+	for i, row := range x {
+		res[i] = RatToBigFrac(row[0])
+	}
+
+	return res, nil
+}
+
+// gaussJordan returns the solution X of m*X = rhs over the rationals for a square matrix m
+func (m Matrix) gaussJordan(op string, rhs Matrix) ([][]*big.Rat, error) {
+	var (
+		n = len(m)
+		w = n + len(rhs[0])
+		a = make([][]*big.Rat, n)
+	)
+
+	for i := range m {
+		a[i] = make([]*big.Rat, w)
+		for j, v := range append(append(Set{}, m[i]...), rhs[i]...) {
+			a[i][j] = new(big.Rat).SetInt64(int64(v))
+		}
+	}
+
+	for c := 0; c < n; c++ {
+		p := c
+		for p < n && a[p][c].Sign() == 0 {
+			p++
+		}
+
+		if p == n {
+			r, _ := m.Rank()
+			return nil, &SingularError{Op: op, Size: Int(n), Rank: r}
+		}
+		a[p], a[c] = a[c], a[p]
+
+		inv := new(big.Rat).Inv(a[c][c])
+		for j := c; j < w; j++ {
+			a[c][j].Mul(a[c][j], inv)
+		}
+
+		for i := 0; i < n; i++ {
+			if i == c || a[i][c].Sign() == 0 {
+				continue
+			}
+
+			f := new(big.Rat).Set(a[i][c])
+			for j := c; j < w; j++ {
+				a[i][j].Sub(a[i][j], new(big.Rat).Mul(f, a[c][j]))
+			}
+		}
+	}
+
+	res := make([][]*big.Rat, n)
+	for i := range a {
+		res[i] = a[i][n:]
+	}
+
+	return res, nil
+}
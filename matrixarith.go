@@ -0,0 +1,235 @@
+package num
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MATRIX ARITHMETIC
+// Operations return new matrices and leave their operands untouched. Dimensions are checked
+// before any work is done so that ragged or mismatched rows are reported as a DimensionError.
+
+// DimensionError is returned when a Matrix has ragged rows or dimensions that are incompatible
+// with an operation
+type DimensionError struct {
+	Op   string   // the operation attempted
+	Dims [][2]Int // rows and columns of each operand, with -1 columns for ragged rows
+}
+
+func (e *DimensionError) Error() string {
+	d := make([]string, len(e.Dims))
+	for i, rc := range e.Dims {
+		if rc[1] < 0 {
+			d[i] = fmt.Sprintf("%dx(ragged)", rc[0])
+		} else {
+			d[i] = fmt.Sprintf("%dx%d", rc[0], rc[1])
+		}
+	}
+
+	return fmt.Sprintf("Matrix %s: incompatible dimensions %s", e.Op, strings.Join(d, " and "))
+}
+
+// dims returns the rows and columns of m with -1 columns if the rows are ragged
+func (m Matrix) dims() [2]Int {
+	if len(m) == 0 {
+		return [2]Int{0, 0}
+	}
+
+	c := Int(len(m[0]))
+	for _, row := range m {
+		if Int(len(row)) != c {
+			return [2]Int{Int(len(m)), -1}
+		}
+	}
+
+	return [2]Int{Int(len(m)), c}
+}
+
+// Dims returns the number of rows and columns of m, or a DimensionError if the rows are ragged
+func (m Matrix) Dims() (rows, cols Int, err error) {
+	d := m.dims()
+	if d[1] < 0 {
+		return 0, 0, &DimensionError{Op: "Dims", Dims: [][2]Int{d}}
+	}
+
+	return d[0], d[1], nil
+}
+
+// square returns the size of m, or a DimensionError naming op if m is not square
+func (m Matrix) square(op string) (Int, error) {
+	d := m.dims()
+	if d[0] != d[1] {
+		return 0, &DimensionError{Op: op, Dims: [][2]Int{d}}
+	}
+
+	return d[0], nil
+}
+
+// Identity returns the n x n identity matrix
+func Identity(n Int) Matrix {
+	m := NewMatrix(n, n)
+	for i := range m {
+		m[i][i] = 1
+	}
+
+	return m
+}
+
+// Add returns m + o
+func (m Matrix) Add(o Matrix) (Matrix, error) {
+	return m.elementwise("Add", o, func(a, b Int) Int { return a + b })
+}
+
+// Sub returns m - o
+func (m Matrix) Sub(o Matrix) (Matrix, error) {
+	return m.elementwise("Sub", o, func(a, b Int) Int { return a - b })
+}
+
+// elementwise returns the matrix of fn applied to the matching elements of m and o
+func (m Matrix) elementwise(op string, o Matrix, fn func(a, b Int) Int) (Matrix, error) {
+	dm, do := m.dims(), o.dims()
+	if dm[1] < 0 || dm != do {
+		return nil, &DimensionError{Op: op, Dims: [][2]Int{dm, do}}
+	}
+
+	res := NewMatrix(dm[0], dm[1])
+	for i, row := range m {
+		for j, v := range row {
+			res[i][j] = fn(v, o[i][j])
+		}
+	}
+
+	return res, nil
+}
+
+// Mul returns the matrix product m * o
+func (m Matrix) Mul(o Matrix) (Matrix, error) {
+	return m.mul("Mul", o, func(a, b, acc Int) Int { return acc + a*b })
+}
+
+// mul returns the product of m and o accumulating each element with fma
+func (m Matrix) mul(op string, o Matrix, fma func(a, b, acc Int) Int) (Matrix, error) {
+	dm, do := m.dims(), o.dims()
+	if dm[1] < 0 || do[1] < 0 || dm[1] != do[0] {
+		return nil, &DimensionError{Op: op, Dims: [][2]Int{dm, do}}
+	}
+
+	res := NewMatrix(dm[0], do[1])
+	for i, row := range m {
+		for k, a := range row {
+			if a == 0 {
+				continue
+			}
+
+			for j, b := range o[k] {
+				res[i][j] = fma(a, b, res[i][j])
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// Scale returns m with every element multiplied by k
+func (m Matrix) Scale(k Int) Matrix {
+	res := make(Matrix, len(m))
+	for i, row := range m {
+		res[i] = make(Set, len(row))
+		for j, v := range row {
+			res[i][j] = v * k
+		}
+	}
+
+	return res
+}
+
+// Transpose returns the transpose of m
+func (m Matrix) Transpose() (Matrix, error) {
+	d := m.dims()
+	if d[1] < 0 {
+		return nil, &DimensionError{Op: "Transpose", Dims: [][2]Int{d}}
+	}
+
+	res := NewMatrix(d[1], d[0])
+	for i, row := range m {
+		for j, v := range row {
+			res[j][i] = v
+		}
+	}
+
+	return res, nil
+}
+
+// Pow returns m^k for a square matrix m and k >= 0 using exponentiation by squaring
+func (m Matrix) Pow(k Int) (Matrix, error) {
+	return m.pow("Pow", k, Matrix.Mul)
+}
+
+// PowMod returns m^k with every element reduced modulo mod into [0, mod). This evaluates the
+// nth term of a linear recurrence mod m from its companion matrix in O(log n) products.
+func (m Matrix) PowMod(k, mod Int) (Matrix, error) {
+	if mod < 1 {
+		return nil, fmt.Errorf("Matrix PowMod requires a positive modulus [%d]", mod)
+	}
+
+	r := make(Matrix, len(m))
+	for i, row := range m {
+		r[i] = make(Set, len(row))
+		for j, v := range row {
+			r[i][j] = Int(v.reduce(mod))
+		}
+	}
+
+	mulMod := func(a, b Matrix) (Matrix, error) {
+		return a.mul("PowMod", b, func(x, y, acc Int) Int {
+			return Int((uint64(acc) + uint64(x.ModMul(y, mod))) % uint64(mod))
+		})
+	}
+
+	res, err := r.pow("PowMod", k, mulMod)
+	if err != nil {
+		return nil, err
+	}
+
+	// m^0 is the identity, which is 0 everywhere mod 1
+	for _, row := range res {
+		for j := range row {
+			row[j] %= mod
+		}
+	}
+
+	return res, nil
+}
+
+// pow returns m^k using mul for each product
+func (m Matrix) pow(op string, k Int, mul func(a, b Matrix) (Matrix, error)) (Matrix, error) {
+	n, err := m.square(op)
+	if err != nil {
+		return nil, err
+	}
+
+	if k < 0 {
+		return nil, fmt.Errorf("Matrix %s requires a non-negative exponent [%d]", op, k)
+	}
+
+	var (
+		res = Identity(n)
+		b   = m
+	)
+
+	for ; k > 0; k >>= 1 {
+		if k&1 == 1 {
+			if res, err = mul(res, b); err != nil {
+				return nil, err
+			}
+		}
+
+		if k > 1 {
+			if b, err = mul(b, b); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return res, nil
+}
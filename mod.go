@@ -0,0 +1,152 @@
+package num
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// MODULAR ARITHMETIC
+// The Mod* functions take a modulus m > 0 and return values in [0, m). Products are
+// formed in 128 bits so results are exact across the full Int range.
+
+// ErrNotInvertible is returned when an inverse modulo m does not exist
+var ErrNotInvertible = errors.New("num: value is not invertible modulo m")
+
+// reduce returns n mod m in [0, m) as a uint64
+func (n Int) reduce(m Int) uint64 {
+	r := n % m
+	if r < 0 {
+		r += m
+	}
+	return uint64(r)
+}
+
+// ModMul returns n*k mod m
+func (n Int) ModMul(k, m Int) Int {
+	return Int(mulMod(n.reduce(m), k.reduce(m), uint64(m)))
+}
+
+// ModPow returns n^e mod m. A negative e raises the inverse of n, which panics if n has no
+// inverse modulo m; use ModInverse first if that is possible.
+func (n Int) ModPow(e, m Int) Int {
+	if e < 0 {
+		inv, err := n.ModInverse(m)
+		if err != nil {
+			panic(err)
+		}
+		n, e = inv, -e
+	}
+
+	return Int(powMod(n.reduce(m), uint64(e), uint64(m)))
+}
+
+// ModInverse returns x such that n*x = 1 mod m
+func (n Int) ModInverse(m Int) (Int, error) {
+	g, x, _ := Int(n.reduce(m)).ExtGCD(m)
+	if g != 1 {
+		return 0, ErrNotInvertible
+	}
+
+	return Int(x.reduce(m)), nil
+}
+
+// GCD returns the Greatest Common Divisor of n and m
+func (n Int) GCD(m Int) Int {
+	for m != 0 {
+		n, m = m, n%m
+	}
+
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// LCM returns the Lowest Common Multiple of n and m
+func (n Int) LCM(m Int) Int {
+	if n == 0 || m == 0 {
+		return 0
+	}
+
+	l := n / n.GCD(m) * m
+	if l < 0 {
+		return -l
+	}
+	return l
+}
+
+// ExtGCD returns the Greatest Common Divisor g of n and m along with Bezout coefficients x and y
+// such that n*x + m*y = g
+func (n Int) ExtGCD(m Int) (g, x, y Int) {
+	oldR, r := n, m
+	oldX, x := Int(1), Int(0)
+	oldY, y := Int(0), Int(1)
+
+	for r != 0 {
+		q := oldR / r
+		oldR, r = r, oldR-q*r
+		oldX, x = x, oldX-q*x
+		oldY, y = y, oldY-q*y
+	}
+
+	if oldR < 0 {
+		return -oldR, -oldX, -oldY
+	}
+	return oldR, oldX, oldY
+}
+
+// LCM returns the Lowest Common Multiple of all items in Set s
+func (s Set) LCM() Int {
+	if len(s) == 0 {
+		return Int(0)
+	}
+
+	res := s[0]
+	for _, n := range s[1:] {
+		res = res.LCM(n)
+	}
+
+	if res < 0 {
+		return -res
+	}
+	return res
+}
+
+// CRT solves the system of congruences x = residues[i] mod moduli[i] using the Chinese Remainder
+// Theorem. The moduli need not be coprime. It returns the solution x along with the modulus m
+// (the LCM of moduli) it is unique to, or an error if the system is inconsistent or m overflows.
+func CRT(residues, moduli Set) (x, m Int, err error) {
+	if len(residues) != len(moduli) {
+		return 0, 0, fmt.Errorf("CRT requires as many residues as moduli [%d|%d]", len(residues), len(moduli))
+	}
+
+	x, m = 0, 1
+	for i, mi := range moduli {
+		if mi <= 0 {
+			return 0, 0, fmt.Errorf("CRT modulus must be positive [%d]", mi)
+		}
+
+		ai := Int(residues[i].reduce(mi))
+
+		// Solve x + m*t = ai mod mi, i.e. m*t = ai - x mod mi
+		g := m.GCD(mi)
+		d := ai - Int(x.reduce(mi))
+		if d%g != 0 {
+			return 0, 0, fmt.Errorf("CRT system is inconsistent at x = %d mod %d", residues[i], mi)
+		}
+
+		step := mi / g
+		if m > Int(math.MaxInt64)/step {
+			return 0, 0, fmt.Errorf("CRT modulus overflows Int")
+		}
+
+		inv, _ := (m / g).ModInverse(step)
+		t := (d / g).ModMul(inv, step)
+
+		// x + m*t < m*step so neither the product nor the sum can overflow
+		x, m = x+m*t, m*step
+	}
+
+	return x, m, nil
+}
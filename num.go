@@ -1,7 +1,9 @@
 package num
 
 import (
-	"math"
+	"errors"
+	"fmt"
+	"math/bits"
 	"strconv"
 	"strings"
 )
@@ -36,36 +38,18 @@ func (n Int) ToSet() Set {
 	return res
 }
 
-// Divisors returns a Set of divisors of n
+// Divisors returns a Set of divisors of n in ascending order
 func (n Int) Divisors() Set {
-	var (
-		div Set
-		lim = Int(math.Sqrt(float64(n)))
-	)
-
-	for i := Int(1); i <= lim; i++ {
-		if n%i == 0 {
-			div = append(div, i)
-			if i*i != n {
-				div = append(div, n/i)
-			}
-		}
+	if n < 1 {
+		return nil
 	}
 
-	return div.Dedupe()
+	return n.Factorize().Divisors()
 }
 
-// PrimeFactors returns the Set of prime factors of n
+// PrimeFactors returns the Set of distinct prime factors of n in ascending order
 func (n Int) PrimeFactors() Set {
-	pf := Set{}
-
-	for _, v := range n.Divisors() {
-		if v.Is(PRIME) {
-			pf = append(pf, v)
-		}
-	}
-
-	return pf
+	return n.Factorize().Primes()
 }
 
 // Factorial returns n!
@@ -84,10 +68,16 @@ func (n Int) Factorial() Int {
 
 // Totient returns the result of Eulers Totient or Phi function of value n
 func (n Int) Totient() Int {
-	ans := n
+	if n < 1 {
+		return 0
+	}
 
-	for _, prime := range n.PrimeFactors() {
-		ans = ans * (prime - 1) / prime
+	ans := Int(1)
+	for _, pp := range n.Factorize() {
+		ans *= pp.Prime - 1
+		for i := Int(1); i < pp.Exp; i++ {
+			ans *= pp.Prime
+		}
 	}
 
 	return ans
@@ -96,32 +86,291 @@ func (n Int) Totient() Int {
 // CfSqrt returns the recurring pattern of the infinite continued fraction of Sqrt(n).
 // Returns nil if n is square
 func (n Int) CfSqrt() Set {
-	var res Set
-	if _, frac := math.Modf(math.Sqrt(float64(n))); frac == 0 {
+	q, err := NewQuadraticIrrational(0, 1, n, 1)
+	if err != nil {
+		return nil
+	}
+
+	pre, period := q.CF()
+	if period == nil {
 		return nil
 	}
 
-	m := Int(math.Floor(math.Sqrt(float64(n))))
-	res = append(res, m)
+	return append(pre, period...)
+}
+
+// ErrNoSqrt is returned when a value has no square root modulo m
+var ErrNoSqrt = errors.New("num: value is not a quadratic residue")
+
+// Legendre returns the Legendre symbol (n/p) for a prime p: 1 if n is a non-zero
+// quadratic residue mod p, -1 if it is a non-residue and 0 if p divides n
+func (n Int) Legendre(p Int) Int {
+	// Every odd n is 1 squared mod 2
+	if p == 2 {
+		return Int(n.reduce(2))
+	}
+
+	return n.Jacobi(p)
+}
 
-	for x, y := Int(1), m; ; {
-		x = (n - y*y) / x
-		res = append(res, (m+y)/x)
-		y = m - (m+y)%x
+// Jacobi returns the Jacobi symbol (n/m) for an odd positive m. It panics for any other m.
+func (n Int) Jacobi(m Int) Int {
+	if m <= 0 || m%2 == 0 {
+		panic(fmt.Sprintf("num: Jacobi symbol is undefined for modulus %d", m))
+	}
 
-		if x <= 1 {
-			break
+	a, t := Int(n.reduce(m)), Int(1)
+	for a != 0 {
+		for a%2 == 0 {
+			a /= 2
+			if r := m % 8; r == 3 || r == 5 {
+				t = -t
+			}
 		}
+
+		// Quadratic reciprocity
+		a, m = m, a
+		if a%4 == 3 && m%4 == 3 {
+			t = -t
+		}
+		a %= m
 	}
 
-	return res
+	if m == 1 {
+		return t
+	}
+	return 0
+}
+
+// Kronecker returns the Kronecker symbol (n/m), which extends the Jacobi symbol to every m
+func (n Int) Kronecker(m Int) Int {
+	if m == 0 {
+		if n == 1 || n == -1 {
+			return 1
+		}
+		return 0
+	}
+
+	t := Int(1)
+	if m < 0 {
+		m = -m
+		if n < 0 {
+			t = -t
+		}
+	}
+
+	if v := bits.TrailingZeros64(uint64(m)); v > 0 {
+		if n%2 == 0 {
+			return 0
+		}
+
+		if r := n.reduce(8); v%2 == 1 && (r == 3 || r == 5) {
+			t = -t
+		}
+		m >>= uint(v)
+	}
+
+	return t * n.Jacobi(m)
+}
+
+// SqrtModPrime returns the smaller of the square roots of n modulo the prime p, using the
+// Tonelli-Shanks algorithm. The other root is p minus the first.
+func (n Int) SqrtModPrime(p Int) (Int, error) {
+	// The search for a non-residue below only terminates for a prime p
+	if !p.Is(PRIME) {
+		return 0, fmt.Errorf("SqrtModPrime requires a prime modulus [%d]", p)
+	}
+
+	a, q := n.reduce(p), uint64(p)
+	if a == 0 || q == 2 {
+		return Int(a), nil
+	}
+
+	if n.Legendre(p) != 1 {
+		return 0, ErrNoSqrt
+	}
+
+	var r uint64
+	if q%4 == 3 {
+		r = powMod(a, (q+1)/4, q)
+	} else {
+		// Write p-1 as d*2^s with d odd and find a non-residue z
+		s := uint(bits.TrailingZeros64(q - 1))
+		d := (q - 1) >> s
+
+		z := Int(2)
+		for z.Legendre(p) != -1 {
+			z++
+		}
+
+		var (
+			c = powMod(uint64(z), d, q)
+			t = powMod(a, d, q)
+			m = s
+		)
+
+		r = powMod(a, (d+1)/2, q)
+		for t != 1 {
+			// Find the least i with t^(2^i) = 1
+			i, t2 := uint(0), t
+			for t2 != 1 {
+				t2 = mulMod(t2, t2, q)
+				i++
+			}
+
+			b := c
+			for j := uint(0); j < m-i-1; j++ {
+				b = mulMod(b, b, q)
+			}
+
+			r, c, m = mulMod(r, b, q), mulMod(b, b, q), i
+			t = mulMod(t, c, q)
+		}
+	}
+
+	if r > q-r {
+		r = q - r
+	}
+
+	return Int(r), nil
+}
+
+// SqrtModPrimePower returns every square root of n modulo p^k in ascending order. Roots of
+// units are lifted from p to p^k with Hensel's lemma. The Set is empty if there are no roots.
+func (n Int) SqrtModPrimePower(p, k Int) Set {
+	pk := Int(1)
+	for i := Int(0); i < k; i++ {
+		pk *= p
+	}
+
+	a := Int(n.reduce(pk))
+	if a == 0 {
+		// x^2 = 0 mod p^k iff p^ceil(k/2) divides x
+		step := Int(1)
+		for i := Int(0); i < (k+1)/2; i++ {
+			step *= p
+		}
+
+		res := Set{}
+		for x := Int(0); x < pk; x += step {
+			res = append(res, x)
+		}
+		return res
+	}
+
+	// Write a as p^(2f) * b with b a unit, which is required for a root to exist
+	e := Int(0)
+	for a%p == 0 {
+		a, e = a/p, e+1
+	}
+
+	if e%2 == 1 {
+		return Set{}
+	}
+
+	var (
+		f  = e / 2
+		j  = k - e
+		pj = pk
+		pf = Int(1)
+	)
+
+	for i := Int(0); i < e; i++ {
+		pj /= p
+	}
+	for i := Int(0); i < f; i++ {
+		pf *= p
+	}
+
+	// Each root y of y^2 = b mod p^j gives the roots pf*(y + t*p^j) mod p^k for 0 <= t < pf
+	res := Set{}
+	for _, y := range unitSqrtModPrimePower(a, p, j, pj) {
+		for t := Int(0); t < pf; t++ {
+			res = append(res, pf*(y+t*pj))
+		}
+	}
+
+	return res.Dedupe()
+}
+
+// unitSqrtModPrimePower returns the square roots of the unit b modulo pj = p^j
+func unitSqrtModPrimePower(b, p, j, pj Int) Set {
+	if p == 2 {
+		switch {
+		case j == 1:
+			return Set{1}
+		case j == 2:
+			if b%4 != 1 {
+				return Set{}
+			}
+			return Set{1, 3}
+		case b%8 != 1:
+			return Set{}
+		}
+
+		// Lift r^2 = b from mod 2^i to mod 2^(i+1)
+		r := Int(1)
+		for i, pw := Int(3), Int(8); i < j; i, pw = i+1, pw*2 {
+			if r.ModMul(r, 2*pw) != b%(2*pw) {
+				r += pw / 2
+			}
+		}
+
+		half := pj / 2
+		return Set{r, pj - r, (r + half) % pj, (pj - r + half) % pj}.Dedupe()
+	}
+
+	r, err := b.SqrtModPrime(p)
+	if err != nil {
+		return Set{}
+	}
+
+	// Newton's iteration r = r - (r^2 - b) / 2r converges quadratically in the p-adic metric
+	for r.ModMul(r, pj) != b%pj {
+		inv, _ := (2 * r).ModInverse(pj)
+		r = Int((r - (r.ModMul(r, pj)-b).ModMul(inv, pj)).reduce(pj))
+	}
+
+	return Set{r, pj - r}.Dedupe()
+}
+
+// SqrtMod returns every square root of n modulo m in ascending order by combining the roots
+// modulo each prime power factor of m with the Chinese Remainder Theorem
+func (n Int) SqrtMod(m Int) Set {
+	res := Set{0}
+	if m == 1 {
+		return res
+	}
+
+	mod := Int(1)
+	for _, pp := range m.Factorize() {
+		roots := n.SqrtModPrimePower(pp.Prime, pp.Exp)
+		if len(roots) == 0 {
+			return Set{}
+		}
+
+		pk := Int(1)
+		for i := Int(0); i < pp.Exp; i++ {
+			pk *= pp.Prime
+		}
+
+		var next Set
+		for _, x := range res {
+			for _, y := range roots {
+				z, _, _ := CRT(Set{x, y}, Set{mod, pk})
+				next = append(next, z)
+			}
+		}
+		res, mod = next, mod*pk
+	}
+
+	return res.Dedupe()
 }
 
 // ADP returns A(bundant), D(eficient) or P(erfect) depending on the value of n,
 // reutrns E if an error occurred (theoretically impossible)
 func (n Int) ADP() string {
-	d := n.Divisors()
-	s := d[:len(d)-1].Sum()
+	s := n.Sigma(1) - n
 	switch {
 	case s == n:
 		return "P"
@@ -0,0 +1,238 @@
+package num
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// MULTIPLICATIVE ORDER
+// The repetend length of 1/d for d coprime to 10 is Int(10).MultiplicativeOrder(d), so these
+// also answer questions about recurring decimals without long division.
+
+// ErrNoPrimitiveRoot is returned for moduli whose group of units is not cyclic
+var ErrNoPrimitiveRoot = errors.New("num: modulus has no primitive root")
+
+// ErrNoLog is returned when a discrete logarithm does not exist
+var ErrNoLog = errors.New("num: discrete logarithm does not exist")
+
+// MultiplicativeOrder returns the smallest k > 0 such that n^k = 1 mod m. n must be coprime to m.
+func (n Int) MultiplicativeOrder(m Int) (Int, error) {
+	if n.GCD(m) != 1 {
+		return 0, ErrNotInvertible
+	}
+
+	// The order divides the Carmichael function so remove every prime factor we can from it
+	ord := m.Carmichael()
+	for _, q := range ord.PrimeFactors() {
+		for ord%q == 0 && n.ModPow(ord/q, m) == 1%m {
+			ord /= q
+		}
+	}
+
+	return ord, nil
+}
+
+// HasPrimitiveRoot returns true if n is 1, 2, 4, p^k or 2p^k for an odd prime p
+func (n Int) HasPrimitiveRoot() bool {
+	if n < 1 {
+		return false
+	}
+
+	if n <= 4 {
+		return true
+	}
+
+	if n%2 == 0 {
+		n /= 2
+	}
+
+	f := n.Factorize()
+	return len(f) == 1 && f[0].Prime != 2
+}
+
+// PrimitiveRoot returns the smallest primitive root modulo n
+func (n Int) PrimitiveRoot() (Int, error) {
+	if !n.HasPrimitiveRoot() {
+		return 0, ErrNoPrimitiveRoot
+	}
+
+	if n == 1 {
+		return 0, nil
+	}
+
+	var (
+		phi = n.Totient()
+		pfs = phi.PrimeFactors()
+	)
+
+	for g := Int(1); g < n; g++ {
+		if g.GCD(n) != 1 {
+			continue
+		}
+
+		ok := true
+		for _, q := range pfs {
+			if g.ModPow(phi/q, n) == 1 {
+				ok = false
+				break
+			}
+		}
+
+		if ok {
+			return g, nil
+		}
+	}
+
+	return 0, ErrNoPrimitiveRoot
+}
+
+// PrimitiveRoots returns every primitive root modulo n in ascending order. The Set is
+// empty if n has none.
+func (n Int) PrimitiveRoots() Set {
+	g, err := n.PrimitiveRoot()
+	if err != nil {
+		return Set{}
+	}
+
+	if n == 1 {
+		return Set{0}
+	}
+
+	// g^k is a primitive root exactly when k is coprime to phi(n)
+	var (
+		res = Set{}
+		phi = n.Totient()
+		x   = Int(1)
+	)
+
+	for k := Int(1); k <= phi; k++ {
+		x = x.ModMul(g, n)
+		if k.GCD(phi) == 1 {
+			res = append(res, x)
+		}
+	}
+
+	return res.Dedupe()
+}
+
+// maxBabyGiant is the largest prime subgroup order solved by baby-step giant-step, which
+// bounds its table at 2^24 entries
+const maxBabyGiant = 1 << 48
+
+// SubgroupError is returned by DiscreteLog when the order of g has a prime factor above 2^48,
+// which is too large for baby-step giant-step
+type SubgroupError struct {
+	Prime Int // the prime factor of the order of g
+}
+
+func (e *SubgroupError) Error() string {
+	return fmt.Sprintf("DiscreteLog: subgroup of prime order %d exceeds the baby-step giant-step limit %d", e.Prime, Int(maxBabyGiant))
+}
+
+// DiscreteLog returns the smallest x >= 0 such that g^x = h mod m. Common factors of g and m
+// are divided out first, then the problem is split over the prime power factors of the order
+// of g with Pohlig-Hellman and each part is solved by baby-step giant-step. A SubgroupError is
+// returned if the order of g has a prime factor above 2^48.
+func DiscreteLog(g, h, m Int) (Int, error) {
+	if m == 1 {
+		return 0, nil
+	}
+
+	g, h = Int(g.reduce(m)), Int(h.reduce(m))
+
+	// Reduce g^x = h mod m to k * g^x' = h' mod m' with g coprime to m'
+	k, add := Int(1)%m, Int(0)
+	for d := g.GCD(m); d != 1; d = g.GCD(m) {
+		if h == k {
+			return add, nil
+		}
+
+		if h%d != 0 {
+			return 0, ErrNoLog
+		}
+
+		h, m, add = h/d, m/d, add+1
+		k = k.ModMul(g/d, m)
+		g, h = Int(g.reduce(m)), Int(h.reduce(m))
+	}
+
+	inv, _ := k.ModInverse(m)
+	h = h.ModMul(inv, m)
+
+	x, err := pohligHellman(g, h, m)
+	if err != nil {
+		return 0, err
+	}
+
+	return x + add, nil
+}
+
+// pohligHellman returns the smallest x with g^x = h mod m for g coprime to m
+func pohligHellman(g, h, m Int) (Int, error) {
+	ord, _ := g.MultiplicativeOrder(m)
+	gInv, _ := g.ModInverse(m)
+
+	fs := ord.Factorize()
+	for _, pp := range fs {
+		if pp.Prime > maxBabyGiant {
+			return 0, &SubgroupError{Prime: pp.Prime}
+		}
+	}
+
+	var residues, moduli Set
+	for _, pp := range fs {
+		var (
+			q     = pp.Prime
+			gamma = g.ModPow(ord/q, m) // generates the subgroup of order q
+			x     = Int(0)
+			qk    = Int(1)
+		)
+
+		// Find x mod q^e one base q digit at a time
+		for e := Int(0); e < pp.Exp; e++ {
+			hk := gInv.ModPow(x, m).ModMul(h, m).ModPow(ord/(qk*q), m)
+
+			d, err := babyGiant(gamma, hk, q, m)
+			if err != nil {
+				return 0, err
+			}
+
+			x += d * qk
+			qk *= q
+		}
+
+		residues, moduli = append(residues, x), append(moduli, qk)
+	}
+
+	x, _, err := CRT(residues, moduli)
+	if err != nil || g.ModPow(x, m) != h%m {
+		return 0, ErrNoLog
+	}
+
+	return x, nil
+}
+
+// babyGiant returns the x in [0, n) with g^x = h mod m using the baby-step giant-step algorithm
+func babyGiant(g, h, n, m Int) (Int, error) {
+	s := Int(math.Ceil(math.Sqrt(float64(n))))
+
+	// Baby steps: g^j for 0 <= j < s
+	baby := make(map[Int]Int, s)
+	for j, x := Int(0), 1%m; j < s; j, x = j+1, x.ModMul(g, m) {
+		if _, ok := baby[x]; !ok {
+			baby[x] = j
+		}
+	}
+
+	// Giant steps: h * g^(-is) for 0 <= i < s
+	gInv, _ := g.ModInverse(m)
+	step := gInv.ModPow(s, m)
+	for i, y := Int(0), h; i < s; i, y = i+1, y.ModMul(step, m) {
+		if j, ok := baby[y]; ok {
+			return i*s + j, nil
+		}
+	}
+
+	return 0, ErrNoLog
+}
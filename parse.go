@@ -0,0 +1,152 @@
+package num
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PARSING
+// ParseFrac and ParseBigFrac accept fractions (3/4), mixed numbers (-1 1/2), decimals (0.125),
+// recurring decimals with the repetend in parentheses (0.1(6)) and scientific notation (1.2e-3).
+
+// maxExponent bounds the exponent accepted in scientific notation
+const maxExponent = 1 << 16
+
+var (
+	fracPattern    = regexp.MustCompile(`^([+-]?\d+)/([+-]?\d+)$`)
+	mixedPattern   = regexp.MustCompile(`^([+-]?)(\d+)\s+(\d+)/(\d+)$`)
+	decimalPattern = regexp.MustCompile(`^([+-]?)(\d*)(?:\.(\d*)(?:\((\d+)\))?)?(?:[eE]([+-]?\d+))?$`)
+)
+
+// ParseFrac parses s as an exact fraction. It returns ErrOverflow if the value does not fit in a Frac.
+func ParseFrac(s string) (*Frac, error) {
+	f, err := ParseBigFrac(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.Frac()
+}
+
+// ParseBigFrac parses s as an exact fraction of arbitrary size
+func ParseBigFrac(s string) (*BigFrac, error) {
+	s = strings.TrimSpace(s)
+
+	if m := fracPattern.FindStringSubmatch(s); m != nil {
+		n, _ := new(big.Int).SetString(m[1], 10)
+		d, _ := new(big.Int).SetString(m[2], 10)
+		if d.Sign() == 0 {
+			return nil, parseError(s, "zero denominator")
+		}
+
+		return NewBigFrac(n, d).Reduce(), nil
+	}
+
+	if m := mixedPattern.FindStringSubmatch(s); m != nil {
+		i, _ := new(big.Int).SetString(m[2], 10)
+		n, _ := new(big.Int).SetString(m[3], 10)
+		d, _ := new(big.Int).SetString(m[4], 10)
+		if d.Sign() == 0 {
+			return nil, parseError(s, "zero denominator")
+		}
+
+		r := new(big.Rat).SetFrac(n, d)
+		r.Add(r, new(big.Rat).SetInt(i))
+		if m[1] == "-" {
+			r.Neg(r)
+		}
+
+		return RatToBigFrac(r), nil
+	}
+
+	m := decimalPattern.FindStringSubmatch(s)
+	if m == nil || m[2]+m[3]+m[4] == "" {
+		return nil, parseError(s, "expected a fraction, mixed number or decimal")
+	}
+
+	var (
+		sign, whole, prefix, rep, exp = m[1], m[2], m[3], m[4], m[5]
+		ten                           = big.NewInt(10)
+	)
+
+	// whole.prefix as an integer over 10^len(prefix)
+	n, _ := new(big.Int).SetString("0"+whole+prefix, 10)
+	r := new(big.Rat).SetFrac(n, new(big.Int).Exp(ten, big.NewInt(int64(len(prefix))), nil))
+
+	// The repetend contributes rep / (10^len(prefix) * (10^len(rep) - 1))
+	if rep != "" {
+		rn, _ := new(big.Int).SetString(rep, 10)
+		rd := new(big.Int).Exp(ten, big.NewInt(int64(len(rep))), nil)
+		rd.Sub(rd, big.NewInt(1))
+		rd.Mul(rd, new(big.Int).Exp(ten, big.NewInt(int64(len(prefix))), nil))
+		r.Add(r, new(big.Rat).SetFrac(rn, rd))
+	}
+
+	if exp != "" {
+		e, err := strconv.Atoi(exp)
+		if err != nil || e > maxExponent || e < -maxExponent {
+			return nil, parseError(s, "exponent out of range")
+		}
+
+		p := new(big.Int).Exp(ten, big.NewInt(int64(abs(e))), nil)
+		if e < 0 {
+			r.Quo(r, new(big.Rat).SetInt(p))
+		} else {
+			r.Mul(r, new(big.Rat).SetInt(p))
+		}
+	}
+
+	if sign == "-" {
+		r.Neg(r)
+	}
+
+	return RatToBigFrac(r), nil
+}
+
+// parseError returns a descriptive error for a failure to parse s
+func parseError(s, reason string) error {
+	return fmt.Errorf("ParseFrac: cannot parse %q: %s", s, reason)
+}
+
+// abs returns the absolute value of i
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+// MarshalText returns f in the form n/d and satisfies encoding.TextMarshaler
+func (f Frac) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText parses any form accepted by ParseFrac into f and satisfies encoding.TextUnmarshaler
+func (f *Frac) UnmarshalText(text []byte) error {
+	g, err := ParseFrac(string(text))
+	if err != nil {
+		return err
+	}
+
+	*f = *g
+	return nil
+}
+
+// MarshalText returns f in the form n/d and satisfies encoding.TextMarshaler
+func (f BigFrac) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText parses any form accepted by ParseBigFrac into f and satisfies encoding.TextUnmarshaler
+func (f *BigFrac) UnmarshalText(text []byte) error {
+	g, err := ParseBigFrac(string(text))
+	if err != nil {
+		return err
+	}
+
+	*f = *g
+	return nil
+}
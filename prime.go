@@ -0,0 +1,88 @@
+package num
+
+import (
+	"math/bits"
+)
+
+// PRIMALITY
+// Miller-Rabin on uint64 is deterministic for the witness set below, see:
+// https://miller-rabin.appspot.com/ (Jim Sinclair, 2011)
+
+// mrWitnesses are sufficient to make Miller-Rabin deterministic for all n < 2^64
+var mrWitnesses = []uint64{2, 325, 9375, 28178, 450775, 9780504, 1795265022}
+
+// smallPrimes are used to shortcut primality testing by trial division
+var smallPrimes = []uint64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53, 59, 61, 67, 71}
+
+// mulMod returns a*b mod m without overflow by using a 128 bit intermediate product
+func mulMod(a, b, m uint64) uint64 {
+	hi, lo := bits.Mul64(a%m, b%m)
+	_, rem := bits.Div64(hi, lo, m)
+	return rem
+}
+
+// powMod returns b^e mod m by exponentiation by squaring
+func powMod(b, e, m uint64) uint64 {
+	if m == 1 {
+		return 0
+	}
+
+	res, b := uint64(1), b%m
+	for ; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			res = mulMod(res, b, m)
+		}
+		b = mulMod(b, b, m)
+	}
+
+	return res
+}
+
+// isPrime returns true if n is prime. The result is exact for every uint64.
+func isPrime(n uint64) bool {
+	if n < 2 {
+		return false
+	}
+
+	for _, p := range smallPrimes {
+		if n%p == 0 {
+			return n == p
+		}
+	}
+
+	// Every composite below 73^2 has a factor in smallPrimes
+	if n < 73*73 {
+		return true
+	}
+
+	// Write n-1 as d*2^s with d odd
+	s := uint(bits.TrailingZeros64(n - 1))
+	d := (n - 1) >> s
+
+	for _, a := range mrWitnesses {
+		a %= n
+		if a == 0 {
+			continue
+		}
+
+		x := powMod(a, d, n)
+		if x == 1 || x == n-1 {
+			continue
+		}
+
+		composite := true
+		for r := uint(1); r < s; r++ {
+			x = mulMod(x, x, n)
+			if x == n-1 {
+				composite = false
+				break
+			}
+		}
+
+		if composite {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,329 @@
+package num
+
+import (
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+// PRIME COUNTING
+// PrimePi uses Lucy_Hedgehog's O(x^(3/4)) dynamic programme over the values floor(x/n) for
+// moderate x and switches to the Meissel-Lehmer formula for larger x. The same dynamic
+// programme gives sums of p^k over the primes p <= x.
+
+// lehmerThreshold is the value of x above which PrimePi uses Meissel-Lehmer
+const lehmerThreshold = 1 << 32
+
+// PrimePi returns the number of primes less than or equal to x
+func PrimePi(x Int) Int {
+	if x < 2 {
+		return 0
+	}
+
+	if x < lehmerThreshold {
+		return Int(lucy(uint64(x), 0, 0))
+	}
+
+	return newLehmer(uint64(x)).pi(uint64(x))
+}
+
+// PrimeSum returns the sum of the primes less than or equal to x. The result is exact
+// provided that it fits in an Int, which holds for x up to roughly 2e10.
+func PrimeSum(x Int) Int {
+	return PrimePowerSum(x, 1, 0)
+}
+
+// PrimePowerSum returns the sum of p^k mod m over the primes p less than or equal to x, where k >= 0.
+// If m is 0 the sum is computed exactly provided the result fits in an Int.
+func PrimePowerSum(x, k, m Int) Int {
+	if x < 2 || m == 1 {
+		return 0
+	}
+
+	return Int(lucy(uint64(x), uint64(k), uint64(m)))
+}
+
+// lucy returns the sum of p^k over the primes p <= x modulo m, where m = 0 stands for 2^64.
+// Only ring operations are used, so results that fit in 64 bits are exact even if the
+// intermediate values wrap.
+func lucy(x, k, m uint64) uint64 {
+	var (
+		r     = isqrt(x)
+		small = make([]uint64, r+1) // small[v] = S(v)
+		large = make([]uint64, r+1) // large[i] = S(x/i)
+	)
+
+	mul := func(a, b uint64) uint64 {
+		if m == 0 {
+			return a * b
+		}
+		return mulMod(a, b, m)
+	}
+
+	sub := func(a, b uint64) uint64 {
+		if m == 0 {
+			return a - b
+		}
+		return (a + m - b) % m
+	}
+
+	// S(v) starts as the sum of i^k over 2 <= i <= v and has the contribution of every
+	// composite with smallest prime factor p removed as each p is processed
+	for i := uint64(1); i <= r; i++ {
+		small[i] = sub(powerSum(i, k, m), 1)
+		large[i] = sub(powerSum(x/i, k, m), 1)
+	}
+
+	for _, p := range append([]uint64{2}, basePrimes(r)...) {
+		var (
+			sp = small[p-1]
+			w  = powWrap(p, k, m)
+			p2 = p * p
+		)
+
+		lim := x / p2
+		if lim > r {
+			lim = r
+		}
+
+		for i := uint64(1); i <= lim; i++ {
+			var v uint64
+			if d := i * p; d <= r {
+				v = large[d]
+			} else {
+				v = small[x/d]
+			}
+			large[i] = sub(large[i], mul(w, sub(v, sp)))
+		}
+
+		for v := r; v >= p2; v-- {
+			small[v] = sub(small[v], mul(w, sub(small[v/p], sp)))
+		}
+	}
+
+	return large[1]
+}
+
+// powWrap returns b^e mod m where m = 0 stands for 2^64
+func powWrap(b, e, m uint64) uint64 {
+	if m != 0 {
+		return powMod(b, e, m)
+	}
+
+	res := uint64(1)
+	for ; e > 0; e >>= 1 {
+		if e&1 == 1 {
+			res *= b
+		}
+		b *= b
+	}
+
+	return res
+}
+
+// powerSum returns the sum of i^k for 1 <= i <= v modulo m, where m = 0 stands for 2^64
+func powerSum(v, k, m uint64) uint64 {
+	switch k {
+	case 0:
+		if m == 0 {
+			return v
+		}
+		return v % m
+
+	case 1:
+		a, b := v, v+1
+		if a%2 == 0 {
+			a /= 2
+		} else {
+			b /= 2
+		}
+
+		if m == 0 {
+			return a * b
+		}
+		return mulMod(a, b, m)
+	}
+
+	// Sum i^k = Sum_j S(k, j) * j! * C(v+1, j+1) where S are Stirling numbers of the second kind
+	var (
+		s   = stirling2(k)
+		res = new(big.Int)
+		c   = big.NewInt(1) // C(v+1, j+1)
+		f   = big.NewInt(1) // j!
+		t   = new(big.Int)
+		n   = new(big.Int).SetUint64(v + 1)
+	)
+
+	for j := uint64(0); j <= k && j <= v; j++ {
+		// C(v+1, j+1) = C(v+1, j) * (v+1-j) / (j+1)
+		c.Mul(c, t.Sub(n, t.SetUint64(j)))
+		c.Quo(c, t.SetUint64(j+1))
+
+		if j > 0 {
+			f.Mul(f, t.SetUint64(j))
+		}
+
+		res.Add(res, t.Mul(t.Mul(s[j], f), c))
+	}
+
+	if m == 0 {
+		return res.Uint64() // the low 64 bits of res
+	}
+
+	return res.Mod(res, new(big.Int).SetUint64(m)).Uint64()
+}
+
+// stirling2 returns the row S(k, 0..k) of Stirling numbers of the second kind
+func stirling2(k uint64) []*big.Int {
+	row := []*big.Int{big.NewInt(1)}
+
+	for n := uint64(1); n <= k; n++ {
+		next := make([]*big.Int, n+1)
+		next[0] = new(big.Int)
+		for j := uint64(1); j <= n; j++ {
+			// S(n, j) = j*S(n-1, j) + S(n-1, j-1)
+			next[j] = new(big.Int).Set(row[j-1])
+			if j < n {
+				next[j].Add(next[j], new(big.Int).Mul(big.NewInt(int64(j)), row[j]))
+			}
+		}
+		row = next
+	}
+
+	return row
+}
+
+// lehmer holds the tables shared between the recursive calls of the Meissel-Lehmer method
+type lehmer struct {
+	lim    uint64   // pi is tabulated below lim
+	primes []uint64 // primes[i] is the (i+1)th prime, up to sqrt(x)
+	sieve  []uint64 // bit n is set if n < lim is prime
+	counts []uint32 // counts[i] is the number of primes below 64i
+	phiTbl [][]uint32
+	phiMod []uint64
+	memo   map[uint64]Int
+}
+
+// phiSmall is the number of primes whose phi values are tabulated over their primorial
+const phiSmall = 6
+
+// newLehmer prepares to count primes up to x
+func newLehmer(x uint64) *lehmer {
+	// Tabulate pi up to roughly x^(2/3) while keeping the table to a few tens of megabytes
+	lim := uint64(math.Cbrt(float64(x)))
+	lim *= lim
+	if lim > 1<<28 {
+		lim = 1 << 28
+	}
+	if r := isqrt(x) + 1; lim < r {
+		lim = r
+	}
+
+	l := &lehmer{
+		lim:    lim,
+		sieve:  make([]uint64, lim/64+1),
+		counts: make([]uint32, lim/64+2),
+		memo:   make(map[uint64]Int),
+	}
+
+	r := isqrt(x)
+	EachPrime(2, Int(lim), func(p Int) bool {
+		if uint64(p) <= r {
+			l.primes = append(l.primes, uint64(p))
+		}
+		l.sieve[p>>6] |= 1 << (uint64(p) & 63)
+		return true
+	})
+
+	for i, w := range l.sieve {
+		l.counts[i+1] = l.counts[i] + uint32(bits.OnesCount64(w))
+	}
+
+	// phiTbl[a][n] = phi(n, a) for n below the product of the first a primes
+	l.phiTbl = make([][]uint32, phiSmall+1)
+	l.phiMod = make([]uint64, phiSmall+1)
+	l.phiMod[0] = 1
+	for a := 1; a <= phiSmall; a++ {
+		l.phiMod[a] = l.phiMod[a-1] * l.primes[a-1]
+
+		tbl := make([]uint32, l.phiMod[a])
+		for n := uint64(1); n < l.phiMod[a]; n++ {
+			tbl[n] = tbl[n-1]
+			if gcd64(n, l.phiMod[a]) == 1 {
+				tbl[n]++
+			}
+		}
+		l.phiTbl[a] = tbl
+	}
+
+	return l
+}
+
+// tabulated returns pi(n) for n < l.lim
+func (l *lehmer) tabulated(n uint64) uint64 {
+	return uint64(l.counts[n>>6]) + uint64(bits.OnesCount64(l.sieve[n>>6]&(1<<(n&63)<<1-1)))
+}
+
+// pi returns the number of primes <= x using Lehmer's formula
+func (l *lehmer) pi(x uint64) Int {
+	if x < l.lim {
+		return Int(l.tabulated(x))
+	}
+
+	if v, ok := l.memo[x]; ok {
+		return v
+	}
+
+	var (
+		a = uint64(l.pi(isqrt(isqrt(x))))
+		b = uint64(l.pi(isqrt(x)))
+		c = uint64(l.pi(icbrt(x)))
+	)
+
+	sum := Int(l.phi(x, a)) + Int((b+a-2)*(b-a+1)/2)
+	for i := a + 1; i <= b; i++ {
+		w := x / l.primes[i-1]
+		sum -= l.pi(w)
+
+		if i <= c {
+			bi := uint64(l.pi(isqrt(w)))
+			for j := i; j <= bi; j++ {
+				sum -= l.pi(w/l.primes[j-1]) - Int(j) + 1
+			}
+		}
+	}
+
+	l.memo[x] = sum
+	return sum
+}
+
+// phi returns the number of integers in [1, x] not divisible by any of the first a primes
+func (l *lehmer) phi(x, a uint64) uint64 {
+	if a <= phiSmall {
+		m := l.phiMod[a]
+		return x/m*uint64(l.phiTbl[a][m-1]) + uint64(l.phiTbl[a][x%m])
+	}
+
+	if x <= l.primes[a-1] {
+		return 1
+	}
+
+	// Only 1 and the primes above p(a) survive below p(a+1)^2
+	if x < l.lim && a < uint64(len(l.primes)) && x < l.primes[a]*l.primes[a] {
+		return l.tabulated(x) - a + 1
+	}
+
+	return l.phi(x, a-1) - l.phi(x/l.primes[a-1], a-1)
+}
+
+// icbrt returns floor(cbrt(n))
+func icbrt(n uint64) uint64 {
+	r := uint64(math.Cbrt(float64(n)))
+	for r > 0 && r*r*r > n {
+		r--
+	}
+	for (r+1)*(r+1)*(r+1) <= n {
+		r++
+	}
+	return r
+}
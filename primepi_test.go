@@ -0,0 +1,83 @@
+package num
+
+import (
+	"testing"
+)
+
+func TestPrimePi(t *testing.T) {
+	known := []struct {
+		x, pi Int
+	}{
+		{0, 0},
+		{1, 0},
+		{2, 1},
+		{10, 4},
+		{100, 25},
+		{1000, 168},
+		{1e6, 78498},
+		{1e9, 50847534},
+		{1e10, 455052511},
+		{1e12, 37607912018},
+		// Either side of lehmerThreshold: 2^32-5 and 2^32+15 are the primes nearest 2^32
+		{lehmerThreshold - 6, 203280220},
+		{lehmerThreshold - 5, 203280221},
+		{lehmerThreshold - 1, 203280221},
+		{lehmerThreshold, 203280221},
+		{lehmerThreshold + 14, 203280221},
+		{lehmerThreshold + 15, 203280222},
+	}
+
+	for _, k := range known {
+		if got := PrimePi(k.x); got != k.pi {
+			t.Errorf("PrimePi(%d) = %d, want %d", k.x, got, k.pi)
+		}
+	}
+}
+
+func TestPrimePiSmall(t *testing.T) {
+	count := Int(0)
+	for x := Int(2); x < 5000; x++ {
+		if x.Is(PRIME) {
+			count++
+		}
+
+		if got := PrimePi(x); got != count {
+			t.Fatalf("PrimePi(%d) = %d, want %d", x, got, count)
+		}
+	}
+}
+
+func TestPrimeSum(t *testing.T) {
+	if got := PrimeSum(2e6); got != 142913828922 {
+		t.Errorf("PrimeSum(2e6) = %d, want 142913828922", got)
+	}
+
+	if got := PrimeSum(1); got != 0 {
+		t.Errorf("PrimeSum(1) = %d, want 0", got)
+	}
+}
+
+func TestPrimePowerSum(t *testing.T) {
+	for _, m := range []Int{0, 2, 7, 1009, 1000000007} {
+		for k := Int(0); k <= 3; k++ {
+			sum := Int(0)
+			for x := Int(2); x < 1500; x++ {
+				if x.Is(PRIME) {
+					pk := Int(1)
+					for i := Int(0); i < k; i++ {
+						pk *= x
+					}
+
+					sum += pk
+					if m != 0 {
+						sum %= m
+					}
+				}
+
+				if got := PrimePowerSum(x, k, m); got != sum {
+					t.Fatalf("PrimePowerSum(%d, %d, %d) = %d, want %d", x, k, m, got, sum)
+				}
+			}
+		}
+	}
+}
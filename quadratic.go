@@ -0,0 +1,320 @@
+package num
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// QuadraticIrrational represents the number (P + sqrt(D)) / Q. Values built with
+// NewQuadraticIrrational always satisfy Q | D - P^2, which keeps every complete quotient of the
+// continued fraction in the same form with integer P and Q.
+type QuadraticIrrational struct {
+	P, D, Q Int
+}
+
+// NewQuadraticIrrational returns (a + b*sqrt(d)) / c in the form (P + sqrt(D)) / Q, or
+// ErrOverflow if D or the scaling needed to keep Q | D - P^2 does not fit in an Int
+func NewQuadraticIrrational(a, b, d, c Int) (*QuadraticIrrational, error) {
+	if c == 0 {
+		return nil, ErrZeroDenominator
+	}
+
+	if d < 0 {
+		return nil, fmt.Errorf("QuadraticIrrational requires a non-negative radicand [%d]", d)
+	}
+
+	// Fold b into the radicand, keeping its sign in the rest of the fraction
+	if b < 0 {
+		v, err := products(Set{-1, a}, Set{-1, b}, Set{-1, c})
+		if err != nil {
+			return nil, err
+		}
+		a, b, c = v[0], v[1], v[2]
+	}
+
+	v, err := products(Set{b, b, d}, Set{a, a})
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := v[0].SubChecked(v[1])
+	if err != nil {
+		return nil, err
+	}
+
+	q := &QuadraticIrrational{P: a, D: v[0], Q: c}
+	if r%q.Q != 0 {
+		m := c
+		if m < 0 {
+			m = -m
+		}
+
+		if v, err = products(Set{q.P, m}, Set{q.D, c, c}, Set{q.Q, m}); err != nil {
+			return nil, err
+		}
+		q.P, q.D, q.Q = v[0], v[1], v[2]
+	}
+
+	return q.reduce(), nil
+}
+
+// products returns the product of each Set in terms, or ErrOverflow
+func products(terms ...Set) (Set, error) {
+	res := make(Set, len(terms))
+	for i, t := range terms {
+		p, err := t.ProductChecked()
+		if err != nil {
+			return nil, err
+		}
+		res[i] = p
+	}
+
+	return res, nil
+}
+
+// pairwise returns op applied to each consecutive pair of v, or the first error
+func pairwise(op func(Int, Int) (Int, error), v Set) (Set, error) {
+	res := make(Set, len(v)/2)
+	for i := range res {
+		r, err := op(v[2*i], v[2*i+1])
+		if err != nil {
+			return nil, err
+		}
+		res[i] = r
+	}
+
+	return res, nil
+}
+
+// reduce divides out the largest common factor of P, Q and sqrt(D) that preserves Q | D - P^2
+func (q *QuadraticIrrational) reduce() *QuadraticIrrational {
+	g := q.P.GCD(q.Q)
+	if g == 0 {
+		return q
+	}
+
+	divs := g.Divisors()
+	for i := len(divs) - 1; i > 0; i-- {
+		k := divs[i]
+		if q.D%(k*k) != 0 {
+			continue
+		}
+
+		p, d, r := q.P/k, q.D/(k*k), q.Q/k
+		if (d-p*p)%r == 0 {
+			q.P, q.D, q.Q = p, d, r
+			break
+		}
+	}
+
+	return q
+}
+
+// Float returns the approximate value of q
+func (q *QuadraticIrrational) Float() float64 {
+	return (float64(q.P) + math.Sqrt(float64(q.D))) / float64(q.Q)
+}
+
+func (q *QuadraticIrrational) String() string {
+	return fmt.Sprintf("(%v + sqrt(%v))/%v", q.P, q.D, q.Q)
+}
+
+// floor returns the integer part of q
+func (q *QuadraticIrrational) floor() Int {
+	s := Int(isqrt(uint64(q.D)))
+
+	// sqrt(D) lies strictly between s and s+1 when D is not square, so flooring with the
+	// nearer bound in the direction of Q gives the exact result
+	if q.Q > 0 {
+		return floorDiv(q.P+s, q.Q)
+	}
+
+	if s*s == q.D {
+		return floorDiv(-q.P-s, -q.Q)
+	}
+	return floorDiv(-q.P-s-1, -q.Q)
+}
+
+// floorDiv returns floor(a/b)
+func floorDiv(a, b Int) Int {
+	d := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		d--
+	}
+	return d
+}
+
+// CF returns the continued fraction of q split into the pre-period (including the leading
+// integer term) and the period that then recurs forever. For square D the value is rational
+// and the whole expansion is returned as the pre-period with a nil period.
+func (q *QuadraticIrrational) CF() (pre, period Set) {
+	pre = Set{}
+	s := Int(isqrt(uint64(q.D)))
+
+	if s*s == q.D {
+		n, d := q.P+s, q.Q
+		if d < 0 {
+			n, d = -n, -d
+		}
+
+		for d != 0 {
+			a := floorDiv(n, d)
+			pre = append(pre, a)
+			n, d = d, n-a*d
+		}
+
+		return pre, nil
+	}
+
+	var (
+		p, r  = q.P, q.Q
+		seen  = make(map[[2]Int]int)
+		terms Set
+	)
+
+	for {
+		if i, ok := seen[[2]Int{p, r}]; ok {
+			return append(pre, terms[:i]...), terms[i:]
+		}
+		seen[[2]Int{p, r}] = len(terms)
+
+		a := (&QuadraticIrrational{P: p, D: q.D, Q: r}).floor()
+		terms = append(terms, a)
+
+		p = a*r - p
+		r = (q.D - p*p) / r
+	}
+}
+
+// Convergents returns a stream of the convergents of q as {h, k} Sets. The channel is closed
+// when either h or k exceeds math.MaxInt64, or after the last term of a rational q.
+func (q *QuadraticIrrational) Convergents() chan Set {
+	c := make(chan Set, 1)
+
+	go func() {
+		defer close(c)
+
+		pre, period := q.CF()
+
+		h0, h1, k0, k1 := Int(0), Int(1), Int(1), Int(0)
+		for i := 0; i < len(pre) || len(period) > 0; i++ {
+			var a Int
+			if i < len(pre) {
+				a = pre[i]
+			} else {
+				a = period[(i-len(pre))%len(period)]
+			}
+
+			h, err := polyStep(a, h1, h0)
+			if err != nil {
+				return
+			}
+
+			k, err := polyStep(a, k1, k0)
+			if err != nil {
+				return
+			}
+
+			h0, h1, k0, k1 = h1, h, k1, k
+			c <- Set{h, k}
+		}
+	}()
+
+	return c
+}
+
+// polyStep returns a*x + y, or ErrOverflow
+func polyStep(a, x, y Int) (Int, error) {
+	t, err := a.MulChecked(x)
+	if err != nil {
+		return 0, err
+	}
+
+	return t.AddChecked(y)
+}
+
+// QuadraticFromCF returns the quadratic irrational whose continued fraction is pre followed by
+// period recurring forever. pre includes the leading integer term and period must not be empty.
+// ErrOverflow is returned if the coefficients do not fit in an Int.
+func QuadraticFromCF(pre, period Set) (*QuadraticIrrational, error) {
+	if len(period) == 0 {
+		return nil, errors.New("num: a quadratic irrational requires a non-empty period")
+	}
+
+	// x = [period, x] gives x = (h1*x + h0) / (k1*x + k0), i.e k1*x^2 + (k0 - h1)*x - h0 = 0
+	h0, h1, k0, k1, err := convergentPair(period)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := k0.SubChecked(h1)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := products(Set{b, b}, Set{4, k1, h0}, Set{-1, b}, Set{2, k1})
+	if err != nil {
+		return nil, err
+	}
+
+	disc, err := v[0].AddChecked(v[1])
+	if err != nil {
+		return nil, err
+	}
+
+	x, err := NewQuadraticIrrational(v[2], 1, disc, v[3])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pre) == 0 {
+		return x, nil
+	}
+
+	// y = [pre, x] = (H1*x + H0) / (K1*x + K0), which is rationalized by multiplying through by
+	// the conjugate of the denominator
+	H0, H1, K0, K1, err := convergentPair(pre)
+	if err != nil {
+		return nil, err
+	}
+
+	// A = H1*x.P + H0*x.Q, B = K1*x.P + K0*x.Q
+	if v, err = products(Set{H1, x.P}, Set{H0, x.Q}, Set{K1, x.P}, Set{K0, x.Q}); err != nil {
+		return nil, err
+	}
+	if v, err = pairwise(Int.AddChecked, v); err != nil {
+		return nil, err
+	}
+	A, B := v[0], v[1]
+
+	// (A*B - H1*K1*D, H1*B - A*K1, B*B - K1*K1*D)
+	if v, err = products(Set{A, B}, Set{H1, K1, x.D}, Set{H1, B}, Set{A, K1}, Set{B, B}, Set{K1, K1, x.D}); err != nil {
+		return nil, err
+	}
+	if v, err = pairwise(Int.SubChecked, v); err != nil {
+		return nil, err
+	}
+
+	return NewQuadraticIrrational(v[0], v[1], x.D, v[2])
+}
+
+// convergentPair returns the last two convergents h0/k0 and h1/k1 of the terms in s, or ErrOverflow
+func convergentPair(s Set) (h0, h1, k0, k1 Int, err error) {
+	h0, h1, k0, k1 = 0, 1, 1, 0
+	for _, a := range s {
+		h, err := polyStep(a, h1, h0)
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+
+		k, err := polyStep(a, k1, k0)
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+
+		h0, h1, k0, k1 = h1, h, k1, k
+	}
+
+	return h0, h1, k0, k1, nil
+}
@@ -0,0 +1,214 @@
+package num
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// LINEAR RECURRENCES
+// Berlekamp-Massey finds the shortest recurrence s[n] = c[0]*s[n-1] + c[1]*s[n-2] + ... +
+// c[L-1]*s[n-L] satisfied by a sequence. 2L terms are enough to determine a recurrence of order
+// L, so a few hundred brute-forced terms recover most recurrences met in practice. NthTerm then
+// reduces x^n modulo the characteristic polynomial (Kitamasa's method), costing O(L^2 log n).
+
+// BerlekampMassey returns the coefficients of the shortest linear recurrence satisfied by s
+// modulo the prime p
+func BerlekampMassey(s Set, p Int) (Set, error) {
+	if !p.Is(PRIME) {
+		return nil, fmt.Errorf("BerlekampMassey requires a prime modulus [%d]", p)
+	}
+
+	var (
+		c, b = Set{1}, Set{1} // connection polynomials, with c[0] = 1
+		l    = 0
+		m    = 1
+		bd   = Int(1) // discrepancy when b was last replaced
+	)
+
+	for n := range s {
+		// d is the discrepancy between s[n] and the current recurrence
+		d := Int(s[n].reduce(p))
+		for i := 1; i <= l; i++ {
+			d = addMod(d, c[i].ModMul(s[n-i], p), p)
+		}
+
+		if d == 0 {
+			m++
+			continue
+		}
+
+		// c -= d/bd * x^m * b
+		inv, err := bd.ModInverse(p)
+		if err != nil {
+			return nil, err
+		}
+
+		var (
+			f = d.ModMul(inv, p)
+			t = append(Set{}, c...)
+		)
+
+		for len(c) < len(b)+m {
+			c = append(c, 0)
+		}
+		for i, v := range b {
+			c[i+m] = addMod(c[i+m], p-f.ModMul(v, p), p)
+		}
+
+		if 2*l <= n {
+			l, b, bd, m = n+1-l, t, d, 1
+		} else {
+			m++
+		}
+	}
+
+	res := make(Set, l)
+	for i := range res {
+		if i+1 < len(c) {
+			res[i] = (p - c[i+1]) % p
+		}
+	}
+
+	return res, nil
+}
+
+// BerlekampMasseyRat returns the coefficients of the shortest linear recurrence satisfied by s
+// over the rationals
+func BerlekampMasseyRat(s Set) []*BigFrac {
+	var (
+		c, b = []*big.Rat{big.NewRat(1, 1)}, []*big.Rat{big.NewRat(1, 1)}
+		l    = 0
+		m    = 1
+		bd   = big.NewRat(1, 1)
+	)
+
+	for n := range s {
+		d := new(big.Rat).SetInt64(int64(s[n]))
+		for i := 1; i <= l; i++ {
+			d.Add(d, new(big.Rat).Mul(c[i], new(big.Rat).SetInt64(int64(s[n-i]))))
+		}
+
+		if d.Sign() == 0 {
+			m++
+			continue
+		}
+
+		var (
+			f = new(big.Rat).Quo(d, bd)
+			t = append([]*big.Rat{}, c...)
+		)
+
+		c = append([]*big.Rat{}, c...)
+		for len(c) < len(b)+m {
+			c = append(c, new(big.Rat))
+		}
+		for i, v := range b {
+			c[i+m] = new(big.Rat).Sub(c[i+m], new(big.Rat).Mul(f, v))
+		}
+
+		if 2*l <= n {
+			l, b, bd, m = n+1-l, t, d, 1
+		} else {
+			m++
+		}
+	}
+
+	res := make([]*BigFrac, l)
+	for i := range res {
+		r := new(big.Rat)
+		if i+1 < len(c) {
+			r.Neg(c[i+1])
+		}
+		res[i] = RatToBigFrac(r)
+	}
+
+	return res
+}
+
+// NthTerm returns s[n] mod m for the sequence with recurrence coefficients c, as returned by
+// BerlekampMassey, and initial terms init, of which there must be at least len(c)
+func NthTerm(c, init Set, n, m Int) (Int, error) {
+	l := len(c)
+
+	switch {
+	case m < 1:
+		return 0, fmt.Errorf("NthTerm requires a positive modulus [%d]", m)
+	case n < 0:
+		return 0, fmt.Errorf("NthTerm requires a non-negative index [%d]", n)
+	case len(init) < l:
+		return 0, fmt.Errorf("NthTerm requires %d initial terms for a recurrence of order %d", l, l)
+	case n < Int(len(init)):
+		return Int(init[n].reduce(m)), nil
+	case l == 0:
+		return 0, nil
+	}
+
+	var (
+		cm = make(Set, l)
+		r  = make(Set, l) // x^n mod the characteristic polynomial
+		x  = make(Set, l) // x^(2^k) mod the characteristic polynomial
+	)
+
+	for i, v := range c {
+		cm[i] = Int(v.reduce(m))
+	}
+
+	r[0] = 1 % m
+	if l == 1 {
+		x[0] = cm[0]
+	} else {
+		x[1] = 1 % m
+	}
+
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			r = polyMulMod(r, x, cm, m)
+		}
+		if n > 1 {
+			x = polyMulMod(x, x, cm, m)
+		}
+	}
+
+	res := Int(0)
+	for i, v := range r {
+		res = addMod(res, v.ModMul(init[i], m), m)
+	}
+
+	return res, nil
+}
+
+// polyMulMod returns a*b reduced modulo x^L - c[0]*x^(L-1) - ... - c[L-1], with coefficients mod m
+func polyMulMod(a, b, c Set, m Int) Set {
+	var (
+		l    = len(c)
+		prod = make(Set, 2*l-1)
+	)
+
+	for i, u := range a {
+		if u == 0 {
+			continue
+		}
+		for j, v := range b {
+			prod[i+j] = addMod(prod[i+j], u.ModMul(v, m), m)
+		}
+	}
+
+	// x^k = c[0]*x^(k-1) + ... + c[L-1]*x^(k-L)
+	for k := len(prod) - 1; k >= l; k-- {
+		if t := prod[k]; t != 0 {
+			for i, v := range c {
+				prod[k-1-i] = addMod(prod[k-1-i], t.ModMul(v, m), m)
+			}
+		}
+	}
+
+	return prod[:l]
+}
+
+// addMod returns a + b mod m for a and b in [0, m)
+func addMod(a, b, m Int) Int {
+	if a >= m-b {
+		return a - (m - b)
+	}
+	return a + b
+}
@@ -6,7 +6,8 @@ import (
 	"math/big"
 )
 
-// Seq returns a channel of numbers for type t
+// Seq returns a channel of numbers for type t. The channel is closed at the first term
+// that would overflow an Int.
 func Seq(t T) chan Int {
 	c := make(chan Int, 1)
 
@@ -15,60 +16,53 @@ func Seq(t T) chan Int {
 
 		switch t {
 		case EVEN:
-			for i := Int(2); i < Int(math.MaxInt64); i += 2 {
+			for i := Int(2); i > 0; i += 2 {
 				c <- i
 			}
 
 		case ODD:
-			for i := Int(1); i < Int(math.MaxInt64); i += 2 {
+			for i := Int(1); i > 0; i += 2 {
 				c <- i
 			}
 
 		case PRIME:
 			c <- 2
 
-			for i := Int(3); i < Int(math.MaxInt64); i += 2 {
+			for i := Int(3); i > 0; i += 2 {
 				if i.Is(PRIME) {
 					c <- i
 				}
 			}
 
-		case TRIANGLE:
-			for i := Int(1); i < Int(math.MaxInt64); i++ {
-				c <- i * (i + 1) / 2
-			}
-
-		case SQUARE:
-			for i := Int(1); i < Int(math.MaxInt64); i++ {
-				c <- i * i
-			}
-
-		case PENTAGONAL:
-			for i := Int(1); i < Int(math.MaxInt64); i++ {
-				c <- i * (3*i - 1) / 2
-			}
-
-		case HEXAGONAL:
-			for i := Int(1); i < Int(math.MaxInt64); i++ {
-				c <- i * (2*i - 1)
-			}
-
-		case HEPTAGONAL:
-			for i := Int(1); i < Int(math.MaxInt64); i++ {
-				c <- i * (5*i - 3) / 2
-			}
-
-		case OCTAGONAL:
-			for i := Int(1); i < Int(math.MaxInt64); i++ {
-				c <- i * (3*i - 2)
+		case TRIANGLE, SQUARE, PENTAGONAL, HEXAGONAL, HEPTAGONAL, OCTAGONAL:
+			// Each figurate number is i*(a*i - b)/d
+			f := map[T][3]Int{
+				TRIANGLE:   {1, -1, 2},
+				SQUARE:     {1, 0, 1},
+				PENTAGONAL: {3, 1, 2},
+				HEXAGONAL:  {2, 1, 1},
+				HEPTAGONAL: {5, 3, 2},
+				OCTAGONAL:  {3, 2, 1},
+			}[t]
+
+			for i := Int(1); ; i++ {
+				n, err := polygonal(i, f[0], f[1], f[2])
+				if err != nil {
+					return
+				}
+				c <- n
 			}
 
 		case FIBONACCI:
 			a, b := Int(0), Int(1)
 			for {
-				a = a + b
-				a, b = b, a
-				c <- a
+				c <- b
+
+				n, err := a.AddChecked(b)
+				if err != nil {
+					return
+				}
+				a, b = b, n
 			}
 
 		default:
@@ -34,26 +34,12 @@ func Range(start, end Int) Set {
 func PrimeSieve(n Int) Set {
 	var res Set
 
-	if n < 2 {
-		return res
-	}
-
-	sieve := make([]bool, n)
-	for i := Int(2); i <= Int(math.Sqrt(float64(n))); i++ {
-		if !sieve[i] {
-			for j := i * i; j < n; j += i {
-				sieve[j] = true
-			}
-		}
-	}
-
-	for i, val := range sieve {
-		if !val {
-			res = append(res, Int(i))
-		}
-	}
+	EachPrime(2, n, func(p Int) bool {
+		res = append(res, p)
+		return true
+	})
 
-	return res[2:]
+	return res
 }
 
 // Contains returns whether or not n exists in set s
@@ -115,22 +101,10 @@ func (s Set) Cmp(t Set) bool {
 
 // GCD returns the Greatest Common Divisor of all items in Set s
 func (s Set) GCD() Int {
-	if len(s) < 2 {
-		return Int(0)
-	}
+	res := Int(0)
 
-	var gcd func(x, y Int) Int
-	gcd = func(x, y Int) Int {
-		for y != 0 {
-			x, y = y, x%y
-		}
-		return x
-	}
-
-	res := gcd(s[0], s[1])
-
-	for i := 2; i < len(s); i++ {
-		res = gcd(res, s[i])
+	for _, n := range s {
+		res = res.GCD(n)
 	}
 
 	return res
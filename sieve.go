@@ -0,0 +1,415 @@
+package num
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"runtime"
+	"sync"
+)
+
+// LinearSieve holds the smallest prime factor of every integer below N, computed with the
+// linear (Euler) sieve. Tables for the totient, Möbius, divisor count and divisor sum
+// functions are derived from it the first time they are requested.
+type LinearSieve struct {
+	N Int
+
+	primes Set
+	spf    []uint32
+
+	phiOnce, muOnce, tauOnce, sigmaOnce sync.Once
+
+	phi   []uint32
+	mu    []int8
+	tau   []uint32
+	sigma []Int
+}
+
+// NewLinearSieve sieves every integer below n. It panics if n exceeds 2^32.
+func NewLinearSieve(n Int) *LinearSieve {
+	if n > 1<<32 {
+		panic(fmt.Sprintf("num: LinearSieve bound %d exceeds 2^32", n))
+	}
+
+	if n < 2 {
+		n = 2
+	}
+
+	s := &LinearSieve{N: n, spf: make([]uint32, n)}
+	for i := Int(2); i < n; i++ {
+		if s.spf[i] == 0 {
+			s.spf[i] = uint32(i)
+			s.primes = append(s.primes, i)
+		}
+
+		// Each composite is crossed off exactly once, by its smallest prime factor
+		for _, p := range s.primes {
+			if p > Int(s.spf[i]) || p*i >= n {
+				break
+			}
+			s.spf[p*i] = uint32(p)
+		}
+	}
+
+	return s
+}
+
+// Primes returns the primes below s.N
+func (s *LinearSieve) Primes() Set {
+	return s.primes
+}
+
+// SPF returns the smallest prime factor of i, or 0 for i < 2
+func (s *LinearSieve) SPF(i Int) Int {
+	return Int(s.spf[i])
+}
+
+// IsPrime returns true if i is prime
+func (s *LinearSieve) IsPrime(i Int) bool {
+	return i > 1 && Int(s.spf[i]) == i
+}
+
+// Factorize returns the prime factorization of i in O(log i) steps
+func (s *LinearSieve) Factorize(i Int) Factorization {
+	f := Factorization{}
+
+	for i > 1 {
+		p := Int(s.spf[i])
+		pp := PrimePower{Prime: p}
+		for ; i%p == 0; i /= p {
+			pp.Exp++
+		}
+		f = append(f, pp)
+	}
+
+	return f
+}
+
+// Phi returns the Euler totient of i
+func (s *LinearSieve) Phi(i Int) Int {
+	s.phiOnce.Do(func() {
+		s.phi = make([]uint32, s.N)
+		if s.N > 1 {
+			s.phi[1] = 1
+		}
+
+		for j := 2; j < len(s.phi); j++ {
+			p := s.spf[j]
+			m := j / int(p)
+			if s.spf[m] == p {
+				s.phi[j] = s.phi[m] * p
+			} else {
+				s.phi[j] = s.phi[m] * (p - 1)
+			}
+		}
+	})
+
+	return Int(s.phi[i])
+}
+
+// Mu returns the Möbius function of i
+func (s *LinearSieve) Mu(i Int) Int {
+	s.muOnce.Do(func() {
+		s.mu = make([]int8, s.N)
+		if s.N > 1 {
+			s.mu[1] = 1
+		}
+
+		for j := 2; j < len(s.mu); j++ {
+			p := s.spf[j]
+			m := j / int(p)
+			if s.spf[m] != p {
+				s.mu[j] = -s.mu[m]
+			}
+		}
+	})
+
+	return Int(s.mu[i])
+}
+
+// Tau returns the number of divisors of i
+func (s *LinearSieve) Tau(i Int) Int {
+	s.tauOnce.Do(func() {
+		s.tau = make([]uint32, s.N)
+		if s.N > 1 {
+			s.tau[1] = 1
+		}
+
+		s.eachPrimePower(func(j, pk, m int) {
+			if pk == j {
+				s.tau[j] = s.tau[m] + 1
+			} else {
+				s.tau[j] = s.tau[pk] * s.tau[j/pk]
+			}
+		})
+	})
+
+	return Int(s.tau[i])
+}
+
+// Sigma returns the sum of the divisors of i
+func (s *LinearSieve) Sigma(i Int) Int {
+	s.sigmaOnce.Do(func() {
+		s.sigma = make([]Int, s.N)
+		if s.N > 1 {
+			s.sigma[1] = 1
+		}
+
+		s.eachPrimePower(func(j, pk, m int) {
+			if pk == j {
+				s.sigma[j] = s.sigma[m]*Int(s.spf[j]) + 1
+			} else {
+				s.sigma[j] = s.sigma[pk] * s.sigma[j/pk]
+			}
+		})
+	})
+
+	return s.sigma[i]
+}
+
+// eachPrimePower calls fn in ascending order for every j >= 2 with pk, the largest power of
+// the smallest prime factor of j that divides j, and m = j / spf(j).
+func (s *LinearSieve) eachPrimePower(fn func(j, pk, m int)) {
+	pk := make([]uint32, s.N)
+
+	for j := 2; j < len(pk); j++ {
+		p := s.spf[j]
+		m := j / int(p)
+		if s.spf[m] == p {
+			pk[j] = pk[m] * p
+		} else {
+			pk[j] = p
+		}
+
+		fn(j, int(pk[j]), m)
+	}
+}
+
+// SEGMENTED SIEVE
+// Only odd numbers are sieved and each is stored as a single bit, so a segment of
+// segmentBits bits covers 2*segmentBits integers in 32KB of memory.
+
+// segmentBits is the number of odd integers sieved per segment
+const segmentBits = 1 << 18
+
+// segmentPool recycles segment bitsets between sieving passes
+var segmentPool = sync.Pool{
+	New: func() interface{} { return make([]uint64, segmentBits/64) },
+}
+
+// segment is a unit of work for the concurrent sieve
+type segment struct {
+	lo, n uint64
+	bits  chan []uint64
+}
+
+// EachPrime calls fn with every prime in [lo, hi) in ascending order, stopping early if fn returns false
+func EachPrime(lo, hi Int, fn func(p Int) bool) {
+	EachPrimeConcurrent(lo, hi, 1, fn)
+}
+
+// EachPrimeConcurrent behaves as EachPrime but sieves segments across the given number of workers.
+// fn is still called from a single goroutine with primes in ascending order. If workers < 1 then
+// GOMAXPROCS workers are used.
+func EachPrimeConcurrent(lo, hi Int, workers int, fn func(p Int) bool) {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	if lo < 2 {
+		lo = 2
+	}
+
+	if hi <= lo {
+		return
+	}
+
+	if lo == 2 {
+		if !fn(2) {
+			return
+		}
+		lo = 3
+	}
+
+	// Sieve odd numbers only, starting from the first odd number >= lo
+	start, end := uint64(lo|1), uint64(hi)
+	if start >= end {
+		return
+	}
+
+	primes := basePrimes(isqrt(end - 1))
+
+	emit := func(lo, n uint64, buf []uint64) bool {
+		for i, w := range buf {
+			for w = ^w; w != 0; w &= w - 1 {
+				j := uint64(i)<<6 + uint64(bits.TrailingZeros64(w))
+				if j >= n {
+					return true
+				}
+
+				if !fn(Int(lo + 2*j)) {
+					return false
+				}
+			}
+		}
+
+		return true
+	}
+
+	// next returns the number of odd integers in the segment beginning at lo
+	next := func(lo uint64) uint64 {
+		if n := (end - lo + 1) / 2; n < segmentBits {
+			return n
+		}
+		return segmentBits
+	}
+
+	if workers == 1 {
+		buf := segmentPool.Get().([]uint64)
+		defer segmentPool.Put(buf)
+
+		for lo := start; lo < end; lo += 2 * segmentBits {
+			n := next(lo)
+			sieveSegment(lo, n, primes, buf)
+			if !emit(lo, n, buf) {
+				return
+			}
+		}
+
+		return
+	}
+
+	var (
+		jobs  = make(chan *segment)
+		order = make(chan *segment, 2*workers)
+		quit  = make(chan struct{})
+		wg    sync.WaitGroup
+	)
+
+	// Segments are queued on order as they are dispatched so that results can be
+	// consumed in sequence, which also bounds the number held in memory.
+	go func() {
+		defer close(jobs)
+		defer close(order)
+
+		for lo := start; lo < end; lo += 2 * segmentBits {
+			s := &segment{lo: lo, n: next(lo), bits: make(chan []uint64, 1)}
+
+			select {
+			case order <- s:
+			case <-quit:
+				return
+			}
+
+			select {
+			case jobs <- s:
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for s := range jobs {
+				buf := segmentPool.Get().([]uint64)
+				sieveSegment(s.lo, s.n, primes, buf)
+				s.bits <- buf
+			}
+		}()
+	}
+
+	defer wg.Wait()
+	defer close(quit)
+
+	for s := range order {
+		buf := <-s.bits
+		ok := emit(s.lo, s.n, buf)
+		segmentPool.Put(buf)
+
+		if !ok {
+			return
+		}
+	}
+}
+
+// PrimesBetween returns a channel of the primes in [lo, hi) in ascending order
+func PrimesBetween(lo, hi Int) chan Int {
+	c := make(chan Int, 1)
+
+	go func() {
+		defer close(c)
+
+		EachPrime(lo, hi, func(p Int) bool {
+			c <- p
+			return true
+		})
+	}()
+
+	return c
+}
+
+// sieveSegment marks the odd composites of the n odd integers starting at the odd number lo
+// in buf, where bit j represents lo+2j. primes must hold every odd prime up to sqrt(lo+2n).
+func sieveSegment(lo, n uint64, primes []uint64, buf []uint64) {
+	for i := range buf {
+		buf[i] = 0
+	}
+
+	hi := lo + 2*n
+	for _, p := range primes {
+		start := p * p
+		if start >= hi {
+			break
+		}
+
+		if start < lo {
+			start = (lo + p - 1) / p * p
+			if start%2 == 0 {
+				start += p
+			}
+		}
+
+		for j := (start - lo) / 2; j < n; j += p {
+			buf[j>>6] |= 1 << (j & 63)
+		}
+	}
+}
+
+// basePrimes returns the odd primes up to and including lim
+func basePrimes(lim uint64) []uint64 {
+	var (
+		res  []uint64
+		comp = make([]uint64, lim/128+1) // bit i represents 2i+1
+	)
+
+	for i := uint64(1); 2*i+1 <= lim; i++ {
+		if comp[i>>6]&(1<<(i&63)) != 0 {
+			continue
+		}
+
+		p := 2*i + 1
+		res = append(res, p)
+
+		for j := p * p / 2; 2*j+1 <= lim; j += p {
+			comp[j>>6] |= 1 << (j & 63)
+		}
+	}
+
+	return res
+}
+
+// isqrt returns floor(sqrt(n))
+func isqrt(n uint64) uint64 {
+	r := uint64(math.Sqrt(float64(n)))
+	for r*r > n {
+		r--
+	}
+	for (r+1)*(r+1) <= n {
+		r++
+	}
+	return r
+}
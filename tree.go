@@ -0,0 +1,276 @@
+package num
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RATIONAL TREES
+// The Stern-Brocot and Calkin-Wilf trees each contain every positive rational exactly once,
+// rooted at 1/1. A node is reached from the root by a path of L and R moves.
+
+// RationalTree identifies a binary tree of the positive rationals
+type RationalTree int
+
+// Rational trees
+const (
+	STERNBROCOT RationalTree = iota // in-order traversal gives the rationals in increasing order
+	CALKINWILF                      // breadth-first traversal gives Stern's diatomic sequence ratios
+)
+
+// ErrTreeRoot is returned when asking for the parent of the root of a RationalTree
+var ErrTreeRoot = errors.New("num: the root of a rational tree has no parent")
+
+// step is a run of k identical moves in a tree path
+type step struct {
+	move byte
+	k    Int
+}
+
+// treeNode returns f in lowest terms, or an error if f is not positive
+func treeNode(f *Frac) (Int, Int, error) {
+	g, err := newReduced(f.Num, f.Den)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if g.Num <= 0 {
+		return 0, 0, fmt.Errorf("Rational tree nodes must be positive [%v]", f)
+	}
+
+	return g.Num, g.Den, nil
+}
+
+// euclidSteps returns the runs of the subtractive Euclidean algorithm taking a/b to 1/1, where
+// L reduces b by a and R reduces a by b
+func euclidSteps(a, b Int) []step {
+	var s []step
+
+	for a != b {
+		if a < b {
+			k := (b - 1) / a
+			s, b = append(s, step{'L', k}), b-k*a
+		} else {
+			k := (a - 1) / b
+			s, a = append(s, step{'R', k}), a-k*b
+		}
+	}
+
+	return s
+}
+
+// Path returns the moves from the root of t to f. The path has one move per level, so its
+// length is the sum of the continued fraction terms of f less one.
+func (t RationalTree) Path(f *Frac) (string, error) {
+	a, b, err := treeNode(f)
+	if err != nil {
+		return "", err
+	}
+
+	s := euclidSteps(a, b)
+
+	// Euclid walks the Stern-Brocot tree down from the root but the Calkin-Wilf tree up to it
+	if t == CALKINWILF {
+		for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+			s[i], s[j] = s[j], s[i]
+		}
+	}
+
+	var sb strings.Builder
+	for _, r := range s {
+		sb.WriteString(strings.Repeat(string(r.move), int(r.k)))
+	}
+
+	return sb.String(), nil
+}
+
+// Frac returns the node of t reached from the root by path, which may only contain L and R
+func (t RationalTree) Frac(path string) (*Frac, error) {
+	var (
+		a, b = Int(1), Int(1) // Calkin-Wilf node
+		l, r = NewFrac(0, 1), NewFrac(1, 0)
+		m    = NewFrac(1, 1) // Stern-Brocot node, the mediant of l and r
+		err  error
+	)
+
+	for i, c := range path {
+		switch {
+		case c != 'L' && c != 'R':
+			return nil, fmt.Errorf("Invalid move %q at position %d of rational tree path", c, i)
+
+		case t == CALKINWILF && c == 'L':
+			b, err = a.AddChecked(b)
+
+		case t == CALKINWILF:
+			a, err = a.AddChecked(b)
+
+		case c == 'L':
+			r = m
+			m, err = mediant(l, r)
+
+		default:
+			l = m
+			m, err = mediant(l, r)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if t == CALKINWILF {
+		return NewFrac(a, b), nil
+	}
+	return m, nil
+}
+
+// Parent returns the parent of f in t, or ErrTreeRoot for 1/1
+func (t RationalTree) Parent(f *Frac) (*Frac, error) {
+	a, b, err := treeNode(f)
+	if err != nil {
+		return nil, err
+	}
+
+	if a == b {
+		return nil, ErrTreeRoot
+	}
+
+	if t == CALKINWILF {
+		if a < b {
+			return NewFrac(a, b-a), nil
+		}
+		return NewFrac(a-b, b), nil
+	}
+
+	// The parent is whichever Stern-Brocot bound of f was created last
+	l, r, err := sbBounds(a, b)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.Num+l.Den > r.Num+r.Den {
+		return l, nil
+	}
+	return r, nil
+}
+
+// Children returns the left and right children of f in t
+func (t RationalTree) Children(f *Frac) (left, right *Frac, err error) {
+	a, b, err := treeNode(f)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if t == CALKINWILF {
+		s, err := a.AddChecked(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		return NewFrac(a, s), NewFrac(s, b), nil
+	}
+
+	l, r, err := sbBounds(a, b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m := NewFrac(a, b)
+	if left, err = mediant(l, m); err != nil {
+		return nil, nil, err
+	}
+	if right, err = mediant(m, r); err != nil {
+		return nil, nil, err
+	}
+
+	return left, right, nil
+}
+
+// BFS returns a stream of the nodes of t in breadth-first order, from the root down to the given
+// depth. The root is at depth 0, so 2^(depth+1) - 1 nodes are produced. The stream closes early
+// if a node would overflow.
+func (t RationalTree) BFS(depth Int) chan *Frac {
+	c := make(chan *Frac, 1)
+
+	go func() {
+		defer close(c)
+
+		if depth < 0 {
+			return
+		}
+
+		if t == CALKINWILF {
+			// Newman's formula steps along each row and wraps onto the next
+			a, b := Int(1), Int(1)
+			for i := Int(1); i < Int(2)<<uint(depth); i++ {
+				c <- NewFrac(a, b)
+
+				// x -> 1/(2*floor(x) - x + 1) = b / (2*(a/b)*b - a + b)
+				d, err := polyStep(2*(a/b), b, b-a)
+				if err != nil {
+					return
+				}
+				a, b = b, d
+			}
+			return
+		}
+
+		// Each row of the Stern-Brocot tree is kept as its nodes with their bounds
+		row := [][3]*Frac{{NewFrac(0, 1), NewFrac(1, 1), NewFrac(1, 0)}}
+		for d := Int(0); d <= depth; d++ {
+			next := make([][3]*Frac, 0, 2*len(row))
+
+			for _, n := range row {
+				c <- n[1]
+
+				if d == depth {
+					continue
+				}
+
+				l, err := mediant(n[0], n[1])
+				if err != nil {
+					return
+				}
+				r, err := mediant(n[1], n[2])
+				if err != nil {
+					return
+				}
+				next = append(next, [3]*Frac{n[0], l, n[1]}, [3]*Frac{n[1], r, n[2]})
+			}
+
+			row = next
+		}
+	}()
+
+	return c
+}
+
+// sbBounds returns the neighbours l < a/b < r whose mediant is a/b in the Stern-Brocot tree,
+// using 1/0 for infinity. They satisfy a*l.Den - b*l.Num = 1 with 0 < l.Den <= b.
+func sbBounds(a, b Int) (l, r *Frac, err error) {
+	ld := b
+	if b > 1 {
+		if ld, err = a.ModInverse(b); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	ln := mulAddDiv(a, ld, -1, b)
+
+	return NewFrac(ln, ld), NewFrac(a-ln, b-ld), nil
+}
+
+// mediant returns (f.Num + g.Num) / (f.Den + g.Den)
+func mediant(f, g *Frac) (*Frac, error) {
+	n, err := f.Num.AddChecked(g.Num)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := f.Den.AddChecked(g.Den)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFrac(n, d), nil
+}